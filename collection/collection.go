@@ -1,11 +1,21 @@
 package collection
 
 import (
+	"bytes"
+	"container/heap"
+	"encoding/gob"
 	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
 	"math/rand"
 	"reflect"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"unsafe"
 )
 
 // Keep is used for merge operations to indicate whether to keep a value and what value to keep.
@@ -14,39 +24,175 @@ type Keep[V any] struct {
 	Value V
 }
 
+// Entry is a typed key-value pair.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
 // Comparator is a function that compares two values and their keys, returning -1, 0, or 1.
 type Comparator[K comparable, V any] func(firstValue, secondValue V, firstKey, secondKey K) int
 
 // Collection is a generic map-like structure with additional utility methods.
-// It is safe for concurrent use.
+// It is safe for concurrent use. Callbacks are called on a snapshot of its items without the lock held, so they
+// may call any method on the collection, except where a method documents that its callback runs under the lock.
+// Those are the methods that check and write in one step, such as SetOrUpdate, ApplyToAll and MergeInto.
 type Collection[K comparable, V any] struct {
 	mu    sync.RWMutex
 	items map[K]V
+	// writeGen is bumped by every store and removal, and is guarded by mu. Optimistic updates compare it to tell
+	// whether the items changed since they were read, without comparing values.
+	writeGen uint64
+
+	changelogEnabled bool
+	changelog        []ChangeRecord[K, V]
+
+	historyEnabled bool
+	historyMax     int
+	history        map[K][]HistoryEntry[K, V]
+
+	watchMu       sync.RWMutex
+	watchers      map[chan CollectionEvent[K, V]]struct{}
+	droppedEvents atomic.Uint64
+	// listeners counts the registered watchers and hooks, so writes can skip keeping changes nobody receives.
+	listeners atomic.Int32
+
+	// hookMu guards hooks. middleware is guarded by mu, since it runs with the write lock held.
+	hookMu     sync.RWMutex
+	hooks      lifecycleHooks[K, V]
+	middleware []CollectionMiddleware[K, V]
+
+	// loader is set by Memoize and never changes afterwards. flights deduplicates loader calls,
+	// and computeFlights the calls of GetOrCompute.
+	loader         func(key K) (V, error)
+	flights        flightGroup[K, V]
+	computeFlights flightGroup[K, V]
+
+	// keyNormalizer is set by NewCaseInsensitive and never changes afterwards.
+	keyNormalizer func(key K) K
+
+	// followers are called with every change while the write lock is held, and are guarded by mu.
+	followers   map[uint64]func(change[K, V])
+	followerSeq uint64
+
+	// stats is set once by EnableAccessStats and never replaced afterwards.
+	stats atomic.Pointer[accessStats[K]]
+
+	// rng, when set by SetRandSource, replaces the global math/rand source; randMu serializes its use.
+	randMu sync.Mutex
+	rng    *rand.Rand
+
+	waiters  atomic.Int32
+	waitMu   sync.Mutex
+	waitCond *sync.Cond
+	waitGen  uint64
 }
 
+// ReadableCollection is the read-only subset of the Collection API.
+// Functions that must not mutate a collection can accept a ReadableCollection; *Collection satisfies it without a wrapper.
+// Its callbacks receive only keys and values, never the collection, and the collections it returns are copies,
+// so no method hands out a way to modify the underlying collection.
+type ReadableCollection[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Has(key K) bool
+	HasAll(keys ...K) bool
+	HasAny(keys ...K) bool
+	Size() int
+	Keys() []K
+	Values() []V
+	Entries() [][2]any
+	TypedEntries() []Entry[K, V]
+	Clone() *Collection[K, V]
+	At(index int) (V, bool)
+	KeyAt(index int) (K, bool)
+	KeysWhere(fn func(value V, key K) bool) []K
+	ValuesWhere(fn func(value V, key K) bool) []V
+	CloneWhere(fn func(value V, key K) bool) *Collection[K, V]
+	FindEntry(fn func(entry Entry[K, V]) bool) (Entry[K, V], bool)
+	All() iter.Seq2[K, V]
+	Equals(other *Collection[K, V]) bool
+	ToJSON() ([]byte, error)
+}
+
+var _ ReadableCollection[string, any] = (*Collection[string, any])(nil)
+
 // New creates a new Collection.
 func New[K comparable, V any]() *Collection[K, V] {
 	return &Collection[K, V]{items: make(map[K]V)}
 }
 
-// Set adds or updates an item in the collection.
-func (c *Collection[K, V]) Set(key K, value V) *Collection[K, V] {
+// NewWithRand creates a new empty Collection whose Random, RandomKey and RandomEntry draw from r.
+func NewWithRand[K comparable, V any](r *rand.Rand) *Collection[K, V] {
+	return New[K, V]().SetRandSource(r)
+}
+
+// NewOf creates a new Collection populated with entries. For duplicate keys, the last entry wins.
+func NewOf[K comparable, V any](entries ...Entry[K, V]) *Collection[K, V] {
+	c := New[K, V]()
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items[key] = value
+	for _, e := range entries {
+		c.items[e.Key] = e.Value
+	}
+	return c
+}
+
+// NewFromSlice creates a new Collection from items, using keyExtractor to derive each item's key.
+// If two items produce the same key, the last one wins. Nil pointer items are skipped.
+func NewFromSlice[K comparable, V any](items []V, keyExtractor func(item V) K) *Collection[K, V] {
+	c := New[K, V]()
+	for _, item := range items {
+		if isNilPointer(item) {
+			continue
+		}
+		c.items[keyExtractor(item)] = item
+	}
+	return c
+}
+
+// Set adds or updates an item in the collection.
+func (c *Collection[K, V]) Set(key K, value V) *Collection[K, V] {
+	c.set(key, value)
 	return c
 }
 
+// set implements Set, and reports whether key existed beforehand and the number of items afterwards.
+func (c *Collection[K, V]) set(key K, value V) (existed bool, size int) {
+	key = c.normalizeKey(key)
+	// A local batch rather than write, so that a plain Set does not allocate
+	b := writeBatch[K, V]{c: c}
+	c.mu.Lock()
+	func() {
+		defer c.mu.Unlock()
+		existed = b.set(key, value)
+		size = len(c.items)
+	}()
+	b.publish()
+	if s := c.stats.Load(); s != nil {
+		s.record(opSet, key, existed)
+	}
+	return existed, size
+}
+
 // Get retrieves an item from the collection.
+// For a collection created by Memoize, a missing item is loaded and cached first.
 func (c *Collection[K, V]) Get(key K) (V, bool) {
+	key = c.normalizeKey(key)
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 	val, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok && c.loader != nil {
+		val, ok = c.load(key)
+	}
+	if s := c.stats.Load(); s != nil {
+		s.record(opGet, key, ok)
+	}
 	return val, ok
 }
 
 // Has checks if a key exists in the collection.
 func (c *Collection[K, V]) Has(key K) bool {
+	key = c.normalizeKey(key)
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	_, ok := c.items[key]
@@ -55,21 +201,42 @@ func (c *Collection[K, V]) Has(key K) bool {
 
 // Delete removes an item from the collection.
 func (c *Collection[K, V]) Delete(key K) bool {
+	_, removed, _ := c.delete(key)
+	return removed
+}
+
+// delete implements Delete, and returns the removed value and the number of items afterwards.
+func (c *Collection[K, V]) delete(key K) (old V, removed bool, size int) {
+	key = c.normalizeKey(key)
+	b := writeBatch[K, V]{c: c}
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	_, existed := c.items[key]
-	delete(c.items, key)
-	return existed
+	func() {
+		defer c.mu.Unlock()
+		old, removed = b.delete(key)
+		size = len(c.items)
+	}()
+	b.publish()
+	if s := c.stats.Load(); s != nil {
+		s.record(opDelete, key, removed)
+	}
+	return old, removed, size
 }
 
 // Clear removes all items from the collection.
 func (c *Collection[K, V]) Clear() *Collection[K, V] {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.items = make(map[K]V)
+	c.clear()
 	return c
 }
 
+// clear implements Clear, and returns the number of items afterwards.
+func (c *Collection[K, V]) clear() (size int) {
+	c.write(func(b *writeBatch[K, V]) {
+		b.clear()
+		size = len(c.items)
+	})
+	return size
+}
+
 // Size returns the number of items in the collection.
 func (c *Collection[K, V]) Size() int {
 	c.mu.RLock()
@@ -95,6 +262,46 @@ func (c *Collection[K, V]) Values() []V {
 	return values
 }
 
+// SortedKeys returns the keys sorted by compare without modifying the collection.
+func (c *Collection[K, V]) SortedKeys(compare func(a, b K) int) []K {
+	c.mu.RLock()
+	keys := c.keysUnlocked()
+	c.mu.RUnlock()
+	slices.SortStableFunc(keys, compare)
+	return keys
+}
+
+// SortedValues returns the values sorted by compare without modifying the collection.
+func (c *Collection[K, V]) SortedValues(compare func(a, b V) int) []V {
+	values := c.Values()
+	slices.SortStableFunc(values, compare)
+	return values
+}
+
+// KeysWhere returns the keys of the items for which fn returns true.
+func (c *Collection[K, V]) KeysWhere(fn func(value V, key K) bool) []K {
+	keys, values := c.snapshot()
+	res := make([]K, 0)
+	for i, k := range keys {
+		if fn(values[i], k) {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// ValuesWhere returns the values of the items for which fn returns true.
+func (c *Collection[K, V]) ValuesWhere(fn func(value V, key K) bool) []V {
+	keys, values := c.snapshot()
+	res := make([]V, 0)
+	for i, k := range keys {
+		if fn(values[i], k) {
+			res = append(res, values[i])
+		}
+	}
+	return res
+}
+
 // Entries returns all key-value pairs in the collection.
 func (c *Collection[K, V]) Entries() [][2]any {
 	c.mu.RLock()
@@ -106,19 +313,49 @@ func (c *Collection[K, V]) Entries() [][2]any {
 	return entries
 }
 
+// TypedEntries returns all key-value pairs in the collection as typed entries.
+func (c *Collection[K, V]) TypedEntries() []Entry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]Entry[K, V], 0, len(c.items))
+	for k, v := range c.items {
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+	return entries
+}
+
 // Clone creates a shallow copy of the collection.
 func (c *Collection[K, V]) Clone() *Collection[K, V] {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	clone := New[K, V]()
+	clone := c.newDerived()
 	for k, v := range c.items {
 		clone.items[k] = v
 	}
 	return clone
 }
 
+// DeepClone creates a fully independent copy of the collection by round-tripping its items through encoding/gob,
+// so values reached through pointers, slices and maps are not shared with the original.
+// Returns an error if any key or value cannot be gob-encoded. Unexported struct fields are not copied.
+func (c *Collection[K, V]) DeepClone() (*Collection[K, V], error) {
+	var buf bytes.Buffer
+	c.mu.RLock()
+	err := gob.NewEncoder(&buf).Encode(c.items)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("collection: deep clone: %w", err)
+	}
+	res := c.newDerived()
+	if err := gob.NewDecoder(&buf).Decode(&res.items); err != nil {
+		return nil, fmt.Errorf("collection: deep clone: %w", err)
+	}
+	return res, nil
+}
+
 // Ensure obtains the value for the given key if it exists, otherwise sets and returns the value provided by the default value generator.
 func (c *Collection[K, V]) Ensure(key K, defaultValueGenerator func(key K, collection *Collection[K, V]) V) V {
+	key = c.normalizeKey(key)
 	c.mu.RLock()
 	if val, ok := c.items[key]; ok {
 		c.mu.RUnlock()
@@ -129,14 +366,89 @@ func (c *Collection[K, V]) Ensure(key K, defaultValueGenerator func(key K, colle
 	// Generate the default value without holding any locks
 	def := defaultValueGenerator(key, c)
 
-	// Now acquire write lock to set the value, but check again if it was set by another goroutine
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	// Now acquire write lock to set the value, but keep a value set by another goroutine while we were generating
+	var res V
+	c.write(func(b *writeBatch[K, V]) {
+		res = b.setDefault(key, def)
+	})
+	return res
+}
+
+// EnsureValue returns the value for the given key if it exists, otherwise sets it to defaultValue and returns that.
+// Unlike SetDefault, an existing key is found under the read lock, so concurrent calls for present keys do not contend.
+func (c *Collection[K, V]) EnsureValue(key K, defaultValue V) V {
+	key = c.normalizeKey(key)
+	c.mu.RLock()
 	if val, ok := c.items[key]; ok {
-		return val // Another goroutine set it while we were generating
+		c.mu.RUnlock()
+		return val
+	}
+	c.mu.RUnlock()
+	return c.SetDefault(key, defaultValue)
+}
+
+// BatchEnsure sets a generated default for each of keys that is missing, like calling Ensure for every key,
+// but checks all keys under one read lock and inserts all defaults under one write lock.
+// Defaults are generated without holding any locks; keys set by another goroutine in the meantime keep their value.
+func (c *Collection[K, V]) BatchEnsure(keys []K, generator func(key K, collection *Collection[K, V]) V) *Collection[K, V] {
+	c.mu.RLock()
+	missing := make([]K, 0, len(keys))
+	seen := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		k = c.normalizeKey(k)
+		if _, ok := c.items[k]; ok {
+			continue
+		}
+		if _, dup := seen[k]; !dup {
+			seen[k] = struct{}{}
+			missing = append(missing, k)
+		}
+	}
+	c.mu.RUnlock()
+	if len(missing) == 0 {
+		return c
+	}
+
+	defaults := make([]V, len(missing))
+	for i, k := range missing {
+		defaults[i] = generator(k, c)
+	}
+
+	c.write(func(b *writeBatch[K, V]) {
+		for i, k := range missing {
+			b.setDefault(k, defaults[i])
+		}
+	})
+	return c
+}
+
+// SetDefault stores value only if key is absent and returns the value that ends up stored for key.
+// The check and insertion happen atomically under a single write lock.
+func (c *Collection[K, V]) SetDefault(key K, value V) V {
+	var res V
+	c.write(func(b *writeBatch[K, V]) {
+		res = b.setDefault(key, value)
+	})
+	return res
+}
+
+// SetOrUpdate stores ifAbsent if key is missing, or the result of ifPresent(existing) if it exists.
+// The check and update happen under a single write lock, so ifPresent must not call methods on the collection.
+// The value is stored as Set stores it, through middleware and access stats.
+func (c *Collection[K, V]) SetOrUpdate(key K, ifAbsent V, ifPresent func(existing V) V) *Collection[K, V] {
+	key = c.normalizeKey(key)
+	value := ifAbsent
+	var existed bool
+	c.write(func(b *writeBatch[K, V]) {
+		if old, ok := b.get(key); ok {
+			value = ifPresent(old)
+		}
+		existed = b.set(key, value)
+	})
+	if s := c.stats.Load(); s != nil {
+		s.record(opSet, key, existed)
 	}
-	c.items[key] = def
-	return def
+	return c
 }
 
 // HasAll checks if all of the provided keys exist in the collection.
@@ -144,7 +456,7 @@ func (c *Collection[K, V]) HasAll(keys ...K) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	for _, k := range keys {
-		if _, ok := c.items[k]; !ok {
+		if _, ok := c.items[c.normalizeKey(k)]; !ok {
 			return false
 		}
 	}
@@ -156,7 +468,7 @@ func (c *Collection[K, V]) HasAny(keys ...K) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	for _, k := range keys {
-		if _, ok := c.items[k]; ok {
+		if _, ok := c.items[c.normalizeKey(k)]; ok {
 			return true
 		}
 	}
@@ -165,6 +477,8 @@ func (c *Collection[K, V]) HasAny(keys ...K) bool {
 
 // First returns the first value(s) in the collection.
 // If amount is 0, returns nil. If amount < 0, returns Last(-amount).
+//
+// Deprecated: The result is a V or a []V depending on amount. Use FirstValue for a typed single value.
 func (c *Collection[K, V]) First(amount ...int) any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -196,6 +510,18 @@ func (c *Collection[K, V]) First(amount ...int) any {
 	return res
 }
 
+// FirstValue returns the first value in the collection.
+// The second return value is false if the collection is empty.
+func (c *Collection[K, V]) FirstValue() (V, bool) {
+	return c.At(0)
+}
+
+// FirstEntry returns the first item in the collection as an Entry.
+// The second return value is false if the collection is empty.
+func (c *Collection[K, V]) FirstEntry() (Entry[K, V], bool) {
+	return c.AtEntry(0)
+}
+
 // FirstKey returns the first key(s) in the collection.
 func (c *Collection[K, V]) FirstKey(amount ...int) any {
 	c.mu.RLock()
@@ -218,6 +544,8 @@ func (c *Collection[K, V]) FirstKey(amount ...int) any {
 }
 
 // Last returns the last value(s) in the collection.
+//
+// Deprecated: The result is a V or a []V depending on amount. Use LastValue for a typed single value.
 func (c *Collection[K, V]) Last(amount ...int) any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -249,6 +577,18 @@ func (c *Collection[K, V]) Last(amount ...int) any {
 	return res
 }
 
+// LastValue returns the last value in the collection.
+// The second return value is false if the collection is empty.
+func (c *Collection[K, V]) LastValue() (V, bool) {
+	return c.At(-1)
+}
+
+// LastEntry returns the last item in the collection as an Entry.
+// The second return value is false if the collection is empty.
+func (c *Collection[K, V]) LastEntry() (Entry[K, V], bool) {
+	return c.AtEntry(-1)
+}
+
 // LastKey returns the last key(s) in the collection.
 func (c *Collection[K, V]) LastKey(amount ...int) any {
 	c.mu.RLock()
@@ -303,16 +643,30 @@ func (c *Collection[K, V]) KeyAt(index int) (K, bool) {
 	return keys[index], true
 }
 
+// AtEntry returns the item at a given index as an Entry, allowing for positive and negative integers.
+func (c *Collection[K, V]) AtEntry(index int) (Entry[K, V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := c.keysUnlocked()
+	if index < 0 {
+		index += len(keys)
+	}
+	if index < 0 || index >= len(keys) {
+		return Entry[K, V]{}, false
+	}
+	return Entry[K, V]{Key: keys[index], Value: c.items[keys[index]]}, true
+}
+
 // Random returns a random value or n unique random values from the collection.
 func (c *Collection[K, V]) Random(amount ...int) any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	keys := c.keysUnlocked()
+	keys := c.randomCandidatesUnlocked()
 	if len(keys) == 0 {
 		return nil
 	}
 	if len(amount) == 0 {
-		k := keys[rand.Intn(len(keys))]
+		k := keys[c.randIntn(len(keys))]
 		return c.items[k]
 	}
 	n := amount[0]
@@ -322,7 +676,7 @@ func (c *Collection[K, V]) Random(amount ...int) any {
 	if n > len(keys) {
 		n = len(keys)
 	}
-	perm := rand.Perm(len(keys))
+	perm := c.randPerm(len(keys))
 	res := make([]V, 0, n)
 	for i := 0; i < n; i++ {
 		res = append(res, c.items[keys[perm[i]]])
@@ -334,12 +688,12 @@ func (c *Collection[K, V]) Random(amount ...int) any {
 func (c *Collection[K, V]) RandomKey(amount ...int) any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	keys := c.keysUnlocked()
+	keys := c.randomCandidatesUnlocked()
 	if len(keys) == 0 {
 		return nil
 	}
 	if len(amount) == 0 {
-		return keys[rand.Intn(len(keys))]
+		return keys[c.randIntn(len(keys))]
 	}
 	n := amount[0]
 	if n <= 0 {
@@ -348,7 +702,7 @@ func (c *Collection[K, V]) RandomKey(amount ...int) any {
 	if n > len(keys) {
 		n = len(keys)
 	}
-	perm := rand.Perm(len(keys))
+	perm := c.randPerm(len(keys))
 	res := make([]K, 0, n)
 	for i := 0; i < n; i++ {
 		res = append(res, keys[perm[i]])
@@ -356,6 +710,43 @@ func (c *Collection[K, V]) RandomKey(amount ...int) any {
 	return res
 }
 
+// RandomEntry returns a random item, or n unique random items, from the collection as an Entry or a []Entry.
+func (c *Collection[K, V]) RandomEntry(amount ...int) any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := c.randomCandidatesUnlocked()
+	if len(keys) == 0 {
+		return nil
+	}
+	if len(amount) == 0 {
+		k := keys[c.randIntn(len(keys))]
+		return Entry[K, V]{Key: k, Value: c.items[k]}
+	}
+	n := amount[0]
+	if n <= 0 {
+		return []Entry[K, V]{}
+	}
+	if n > len(keys) {
+		n = len(keys)
+	}
+	perm := c.randPerm(len(keys))
+	res := make([]Entry[K, V], 0, n)
+	for i := 0; i < n; i++ {
+		k := keys[perm[i]]
+		res = append(res, Entry[K, V]{Key: k, Value: c.items[k]})
+	}
+	return res
+}
+
+// SetRandSource makes Random, RandomKey and RandomEntry draw from r instead of the global math/rand source, and returns the collection.
+// With a seeded source, the same items and calls produce the same selections. A nil r restores the global source.
+func (c *Collection[K, V]) SetRandSource(r *rand.Rand) *Collection[K, V] {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	c.rng = r
+	return c
+}
+
 // Reverse reverses the order of the collection in place.
 func (c *Collection[K, V]) Reverse() *Collection[K, V] {
 	c.mu.Lock()
@@ -374,11 +765,10 @@ func (c *Collection[K, V]) Reverse() *Collection[K, V] {
 
 // Find returns the first value for which fn returns true.
 func (c *Collection[K, V]) Find(fn func(value V, key K, collection *Collection[K, V]) bool) (V, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for k, v := range c.items {
-		if fn(v, k, c) {
-			return v, true
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if fn(values[i], k, c) {
+			return values[i], true
 		}
 	}
 	var zero V
@@ -387,10 +777,9 @@ func (c *Collection[K, V]) Find(fn func(value V, key K, collection *Collection[K
 
 // FindKey returns the first key for which fn returns true.
 func (c *Collection[K, V]) FindKey(fn func(value V, key K, collection *Collection[K, V]) bool) (K, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for k, v := range c.items {
-		if fn(v, k, c) {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if fn(values[i], k, c) {
 			return k, true
 		}
 	}
@@ -400,14 +789,10 @@ func (c *Collection[K, V]) FindKey(fn func(value V, key K, collection *Collectio
 
 // FindLast returns the last value for which fn returns true.
 func (c *Collection[K, V]) FindLast(fn func(value V, key K, collection *Collection[K, V]) bool) (V, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	keys := c.keysUnlocked()
+	keys, values := c.snapshot()
 	for i := len(keys) - 1; i >= 0; i-- {
-		k := keys[i]
-		v := c.items[k]
-		if fn(v, k, c) {
-			return v, true
+		if fn(values[i], keys[i], c) {
+			return values[i], true
 		}
 	}
 	var zero V
@@ -416,42 +801,114 @@ func (c *Collection[K, V]) FindLast(fn func(value V, key K, collection *Collecti
 
 // FindLastKey returns the last key for which fn returns true.
 func (c *Collection[K, V]) FindLastKey(fn func(value V, key K, collection *Collection[K, V]) bool) (K, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	keys := c.keysUnlocked()
+	keys, values := c.snapshot()
 	for i := len(keys) - 1; i >= 0; i-- {
-		k := keys[i]
-		v := c.items[k]
-		if fn(v, k, c) {
-			return k, true
+		if fn(values[i], keys[i], c) {
+			return keys[i], true
 		}
 	}
 	var zero K
 	return zero, false
 }
 
+// FindEntry returns the first item, as an Entry, for which fn returns true.
+// fn is called on a snapshot without the lock held, so it may call any method on the collection.
+func (c *Collection[K, V]) FindEntry(fn func(entry Entry[K, V]) bool) (Entry[K, V], bool) {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if entry := (Entry[K, V]{Key: k, Value: values[i]}); fn(entry) {
+			return entry, true
+		}
+	}
+	return Entry[K, V]{}, false
+}
+
 // Sweep removes items that satisfy the provided filter function. Returns the number of removed entries.
+// fn is evaluated on a snapshot without holding the lock. Matching items are then removed under the write lock.
+// If the collection was written to in the meantime, an item is removed only if its value is still the one fn saw
+// per reflect.DeepEqual, so an item replaced concurrently is never removed on the strength of its old value;
+// in that case items whose values never equal themselves, such as NaN, are left in place.
 func (c *Collection[K, V]) Sweep(fn func(value V, key K, collection *Collection[K, V]) bool) int {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	count := 0
-	for k, v := range c.items {
-		if fn(v, k, c) {
-			delete(c.items, k)
-			count++
+	keys, values, gen := c.snapshotGen()
+	matched := make([]Entry[K, V], 0)
+	for i, k := range keys {
+		if fn(values[i], k, c) {
+			matched = append(matched, Entry[K, V]{Key: k, Value: values[i]})
 		}
 	}
+	count := 0
+	c.write(func(b *writeBatch[K, V]) {
+		unchanged := c.writeGen == gen
+		for _, e := range matched {
+			if current, ok := b.get(e.Key); !ok || (!unchanged && !reflect.DeepEqual(current, e.Value)) {
+				continue
+			}
+			if _, ok := b.delete(e.Key); ok {
+				count++
+			}
+		}
+	})
 	return count
 }
 
+// Fill sets every existing item to value under a single write lock and returns the collection. No keys are added or removed.
+func (c *Collection[K, V]) Fill(value V) *Collection[K, V] {
+	c.write(func(b *writeBatch[K, V]) {
+		for _, k := range c.keysUnlocked() {
+			b.set(k, value)
+		}
+	})
+	return c
+}
+
+// ReplaceAllWhere sets every item whose value satisfies predicate to newValue, and returns the number of items replaced.
+// The whole replacement happens under a single write lock, so predicate must not call methods on the collection.
+func (c *Collection[K, V]) ReplaceAllWhere(predicate func(value V) bool, newValue V) int {
+	count := 0
+	c.write(func(b *writeBatch[K, V]) {
+		for _, k := range c.keysUnlocked() {
+			if predicate(c.items[k]) {
+				b.set(k, newValue)
+				count++
+			}
+		}
+	})
+	return count
+}
+
+// ApplyToAll replaces every value with fn(value, key) under a single write lock and returns the collection.
+// It is the in-place counterpart of MapCollectionValues; fn must not call methods on the collection.
+func (c *Collection[K, V]) ApplyToAll(fn func(value V, key K) V) *Collection[K, V] {
+	c.write(func(b *writeBatch[K, V]) {
+		for _, k := range c.keysUnlocked() {
+			b.set(k, fn(c.items[k], k))
+		}
+	})
+	return c
+}
+
 // Filter returns a new collection containing only the items for which fn returns true.
 func (c *Collection[K, V]) Filter(fn func(value V, key K, collection *Collection[K, V]) bool) *Collection[K, V] {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	res := New[K, V]()
-	for k, v := range c.items {
-		if fn(v, k, c) {
-			res.items[k] = v
+	return c.CloneWhere(func(value V, key K) bool {
+		return fn(value, key, c)
+	})
+}
+
+// FilterNot returns a new collection containing only the items for which fn returns false.
+func (c *Collection[K, V]) FilterNot(fn func(value V, key K, collection *Collection[K, V]) bool) *Collection[K, V] {
+	return c.CloneWhere(func(value V, key K) bool {
+		return !fn(value, key, c)
+	})
+}
+
+// CloneWhere returns a new collection containing only the items for which fn returns true.
+// Unlike Clone followed by Sweep, items that do not match are never copied.
+func (c *Collection[K, V]) CloneWhere(fn func(value V, key K) bool) *Collection[K, V] {
+	keys, values := c.snapshot()
+	res := c.newDerived()
+	for i, k := range keys {
+		if fn(values[i], k) {
+			res.items[k] = values[i]
 		}
 	}
 	return res
@@ -459,40 +916,119 @@ func (c *Collection[K, V]) Filter(fn func(value V, key K, collection *Collection
 
 // Partition splits the collection into two collections: the first contains items that passed, the second those that failed.
 func (c *Collection[K, V]) Partition(fn func(value V, key K, collection *Collection[K, V]) bool) (*Collection[K, V], *Collection[K, V]) {
+	keys, values := c.snapshot()
+	pass := c.newDerived()
+	fail := c.newDerived()
+	for i, k := range keys {
+		if fn(values[i], k, c) {
+			pass.items[k] = values[i]
+		} else {
+			fail.items[k] = values[i]
+		}
+	}
+	return pass, fail
+}
+
+// ChunkBy splits the collection into runs of consecutive items, starting a new chunk whenever fn returns true
+// for an item and the one before it. Since Go maps are unordered, items are visited in ascending natural key order
+// (numbers and strings compare naturally, other types by their formatted form). An empty collection yields no chunks.
+func (c *Collection[K, V]) ChunkBy(fn func(current, prev V, currentKey, prevKey K) bool) []*Collection[K, V] {
+	c.mu.RLock()
+	keys := c.keysUnlocked()
+	slices.SortFunc(keys, func(a, b K) int {
+		return compareNatural(a, b)
+	})
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i] = c.items[k]
+	}
+	c.mu.RUnlock()
+
+	var chunks []*Collection[K, V]
+	var chunk *Collection[K, V]
+	for i, k := range keys {
+		if i == 0 || fn(values[i], values[i-1], k, keys[i-1]) {
+			chunk = c.newDerived()
+			chunks = append(chunks, chunk)
+		}
+		chunk.items[k] = values[i]
+	}
+	return chunks
+}
+
+// SplitAt returns two new collections: the items at positions [0, index) and those at [index, Size()).
+// Negative indices count from the end, and out-of-range indices are clamped.
+func (c *Collection[K, V]) SplitAt(index int) (*Collection[K, V], *Collection[K, V]) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	pass := New[K, V]()
-	fail := New[K, V]()
-	for k, v := range c.items {
-		if fn(v, k, c) {
-			pass.items[k] = v
+	keys := c.keysUnlocked()
+	if index < 0 {
+		index += len(keys)
+	}
+	index = max(0, min(index, len(keys)))
+
+	head, tail := c.newDerived(), c.newDerived()
+	for i, k := range keys {
+		if i < index {
+			head.items[k] = c.items[k]
 		} else {
-			fail.items[k] = v
+			tail.items[k] = c.items[k]
 		}
 	}
-	return pass, fail
+	return head, tail
 }
 
-// FlatMap maps each item into a collection, then joins the results into a single collection.
-func (c *Collection[K, V]) FlatMap(fn func(value V, key K, collection *Collection[K, V]) *Collection[K, V]) *Collection[K, V] {
+// UniqueBy returns a new collection keeping only the first item encountered for each distinct result of keySelector.
+// The results of keySelector must be comparable. keySelector is called on a snapshot without the lock held.
+func (c *Collection[K, V]) UniqueBy(keySelector func(value V, key K) any) *Collection[K, V] {
+	keys, values := c.snapshot()
+	res := c.newDerived()
+	seen := make(map[any]struct{}, len(keys))
+	for i, k := range keys {
+		v := values[i]
+		id := keySelector(v, k)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		res.items[k] = v
+	}
+	return res
+}
+
+// Compact returns a new collection without the items whose value is the zero value of its type, including nil pointers and interfaces.
+func (c *Collection[K, V]) Compact() *Collection[K, V] {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	result := New[K, V]()
+	res := c.newDerived()
 	for k, v := range c.items {
-		sub := fn(v, k, c)
+		if !isZero(v) {
+			res.items[k] = v
+		}
+	}
+	return res
+}
+
+// FlatMap maps each item into a collection, then joins the results into a single collection.
+func (c *Collection[K, V]) FlatMap(fn func(value V, key K, collection *Collection[K, V]) *Collection[K, V]) *Collection[K, V] {
+	keys, values := c.snapshot()
+	result := New[K, V]()
+	for i, k := range keys {
+		sub := fn(values[i], k, c)
+		sub.mu.RLock()
 		for subk, subv := range sub.items {
 			result.items[subk] = subv
 		}
+		sub.mu.RUnlock()
 	}
 	return result
 }
 
 // Some returns true if any item passes the test.
 func (c *Collection[K, V]) Some(fn func(value V, key K, collection *Collection[K, V]) bool) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for k, v := range c.items {
-		if fn(v, k, c) {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if fn(values[i], k, c) {
 			return true
 		}
 	}
@@ -501,10 +1037,9 @@ func (c *Collection[K, V]) Some(fn func(value V, key K, collection *Collection[K
 
 // Every returns true if all items pass the test.
 func (c *Collection[K, V]) Every(fn func(value V, key K, collection *Collection[K, V]) bool) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for k, v := range c.items {
-		if !fn(v, k, c) {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if !fn(values[i], k, c) {
 			return false
 		}
 	}
@@ -513,14 +1048,71 @@ func (c *Collection[K, V]) Every(fn func(value V, key K, collection *Collection[
 
 // Each executes fn for each element and returns the collection.
 func (c *Collection[K, V]) Each(fn func(value V, key K, collection *Collection[K, V])) *Collection[K, V] {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	for k, v := range c.items {
-		fn(v, k, c)
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		fn(values[i], k, c)
 	}
 	return c
 }
 
+// ForEachEntry executes fn for each item, passed as an Entry, and returns the collection.
+func (c *Collection[K, V]) ForEachEntry(fn func(entry Entry[K, V], collection *Collection[K, V])) *Collection[K, V] {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		fn(Entry[K, V]{Key: k, Value: values[i]}, c)
+	}
+	return c
+}
+
+// EachWithBreak executes fn for each element until fn returns false, and returns the collection.
+func (c *Collection[K, V]) EachWithBreak(fn func(value V, key K, collection *Collection[K, V]) bool) *Collection[K, V] {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if !fn(values[i], k, c) {
+			break
+		}
+	}
+	return c
+}
+
+// ForEachUntil executes fn for each element until fn returns a non-nil error, which it returns.
+// It returns nil if fn succeeds for every element.
+func (c *Collection[K, V]) ForEachUntil(fn func(value V, key K, collection *Collection[K, V]) error) error {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		if err := fn(values[i], k, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tee executes both fn1 and fn2 for each element in a single pass, and returns the collection.
+// Both functions observe the same snapshot of the collection, taken before the first call, without the lock held.
+func (c *Collection[K, V]) Tee(fn1, fn2 func(value V, key K)) *Collection[K, V] {
+	keys, values := c.snapshot()
+	for i, k := range keys {
+		fn1(values[i], k)
+		fn2(values[i], k)
+	}
+	return c
+}
+
+// Validate runs every validator against every item and returns all the errors they report,
+// or nil if every item passes. Validators are called on a snapshot of the items without the lock held.
+func (c *Collection[K, V]) Validate(validators ...func(key K, value V) error) []error {
+	keys, values := c.snapshot()
+	var errs []error
+	for i, k := range keys {
+		for _, validate := range validators {
+			if err := validate(k, values[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
 // Tap runs a function on the collection and returns the collection.
 func (c *Collection[K, V]) Tap(fn func(collection *Collection[K, V])) *Collection[K, V] {
 	fn(c)
@@ -540,6 +1132,62 @@ func (c *Collection[K, V]) Concat(collections ...*Collection[K, V]) *Collection[
 	return result
 }
 
+// Intersperse returns a new collection with a separatorValue entry inserted between every consecutive pair of items of c.
+// Every separator key is separatorKey suffixed with an increasing index ("sep_0", "sep_1", ...), skipping keys that
+// already exist in c. Intersperse is a function rather than a method because separator keys are built from strings,
+// so only collections with string keys are accepted.
+func Intersperse[K ~string, V any](c *Collection[K, V], separatorKey K, separatorValue V) *Collection[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := c.newDerived()
+	next := 0
+	for i, k := range c.keysUnlocked() {
+		if i > 0 {
+			for {
+				key := K(fmt.Sprintf("%s_%d", separatorKey, next))
+				next++
+				if _, exists := c.items[key]; !exists {
+					res.items[key] = separatorValue
+					break
+				}
+			}
+		}
+		res.items[k] = c.items[k]
+	}
+	return res
+}
+
+// CopyTo inserts all items of this collection into target, overwriting existing keys, and returns target.
+func (c *Collection[K, V]) CopyTo(target *Collection[K, V]) *Collection[K, V] {
+	if c == target {
+		return target
+	}
+	writePair(c, false, target, true, func(_, tb *writeBatch[K, V]) {
+		for k, v := range c.items {
+			tb.set(k, v)
+		}
+	})
+	return target
+}
+
+// MoveEntry atomically removes the item for key from this collection and stores it in target.
+// Returns false, leaving both collections unchanged, if the key does not exist.
+func (c *Collection[K, V]) MoveEntry(key K, target *Collection[K, V]) bool {
+	if c == target {
+		return c.Has(key)
+	}
+	moved := false
+	writePair(c, true, target, true, func(cb, tb *writeBatch[K, V]) {
+		v, ok := cb.delete(key)
+		if !ok {
+			return
+		}
+		tb.set(key, v)
+		moved = true
+	})
+	return moved
+}
+
 // Equals checks if this collection shares identical items with another.
 func (c *Collection[K, V]) Equals(other *Collection[K, V]) bool {
 	if c == other {
@@ -561,14 +1209,63 @@ func (c *Collection[K, V]) Equals(other *Collection[K, V]) bool {
 	return true
 }
 
+// Compare returns -1, 0, or 1 reflecting the lexicographic order of this collection and the other
+// when the entries of both are sorted and compared pair by pair. A collection that is a prefix of the other sorts first.
+// Entries are ordered by compare, then by the natural order of their keys, then of their values, so ties in compare
+// never depend on map iteration order and Compare returns 0 exactly when the collections are equal per Equals.
+// Values that differ per Equals but cannot be ordered, such as NaN, are the exception and compare as equal.
+func (c *Collection[K, V]) Compare(other *Collection[K, V], compare Comparator[K, V]) int {
+	if c == other {
+		return 0
+	}
+	// Copy both sides under locks taken in a fixed order, then sort and call compare without any lock held
+	unlock := lockPair(c, false, other, false)
+	items, otherItems := maps.Clone(c.items), maps.Clone(other.items)
+	unlock()
+
+	compareEntries := func(v, ov V, k, ok K) int {
+		if r := compare(v, ov, k, ok); r != 0 {
+			return sign(r)
+		}
+		if k != ok {
+			if r := compareNatural(k, ok); r != 0 {
+				return r
+			}
+			return strings.Compare(fmt.Sprintf("%#v", k), fmt.Sprintf("%#v", ok))
+		}
+		if reflect.DeepEqual(v, ov) {
+			return 0
+		}
+		if r := compareNatural(v, ov); r != 0 {
+			return r
+		}
+		return strings.Compare(fmt.Sprintf("%#v", v), fmt.Sprintf("%#v", ov))
+	}
+	sortedKeys := func(items map[K]V) []K {
+		keys := make([]K, 0, len(items))
+		for k := range items {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b K) int {
+			return compareEntries(items[a], items[b], a, b)
+		})
+		return keys
+	}
+
+	keys, otherKeys := sortedKeys(items), sortedKeys(otherItems)
+	for i := 0; i < min(len(keys), len(otherKeys)); i++ {
+		if r := compareEntries(items[keys[i]], otherItems[otherKeys[i]], keys[i], otherKeys[i]); r != 0 {
+			return r
+		}
+	}
+	return sign(len(keys) - len(otherKeys))
+}
+
 // Sort sorts the items of a collection in place and returns it.
 func (c *Collection[K, V]) Sort(compare Comparator[K, V]) *Collection[K, V] {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	keys := c.keysUnlocked()
-	sort.SliceStable(keys, func(i, j int) bool {
-		return compare(c.items[keys[i]], c.items[keys[j]], keys[i], keys[j]) < 0
-	})
+	keys := c.sortedKeysUnlocked(compare)
 	newItems := make(map[K]V, len(c.items))
 	for _, k := range keys {
 		newItems[k] = c.items[k]
@@ -577,13 +1274,31 @@ func (c *Collection[K, V]) Sort(compare Comparator[K, V]) *Collection[K, V] {
 	return c
 }
 
+// TopN returns a new collection with the n items that Sort(compare) would place at the end, without fully sorting.
+// If n exceeds the size of the collection, all items are returned. compare is called on a snapshot without the lock held.
+func (c *Collection[K, V]) TopN(n int, compare Comparator[K, V]) *Collection[K, V] {
+	snap := c.Clone()
+	return snap.selectNUnlocked(n, func(a, b K) bool {
+		return compare(snap.items[a], snap.items[b], a, b) < 0
+	})
+}
+
+// BottomN returns a new collection with the n items that Sort(compare) would place at the start, without fully sorting.
+// If n exceeds the size of the collection, all items are returned. compare is called on a snapshot without the lock held.
+func (c *Collection[K, V]) BottomN(n int, compare Comparator[K, V]) *Collection[K, V] {
+	snap := c.Clone()
+	return snap.selectNUnlocked(n, func(a, b K) bool {
+		return compare(snap.items[a], snap.items[b], a, b) > 0
+	})
+}
+
 // Intersection returns a new collection containing the items where the key is present in both collections.
 func (c *Collection[K, V]) Intersection(other *Collection[K, any]) *Collection[K, V] {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	other.mu.RLock()
 	defer other.mu.RUnlock()
-	res := New[K, V]()
+	res := c.newDerived()
 	for k, v := range c.items {
 		if _, ok := other.items[k]; ok {
 			res.items[k] = v
@@ -607,13 +1322,61 @@ func (c *Collection[K, V]) Union(other *Collection[K, V]) *Collection[K, V] {
 	return res
 }
 
+// MergeWith returns a new collection containing the items of both collections.
+// For keys present in both, the stored value is resolve(existing, incoming), where existing comes from this collection.
+func (c *Collection[K, V]) MergeWith(other *Collection[K, V], resolve func(existing, incoming V) V) *Collection[K, V] {
+	if other == c {
+		other = c.Clone()
+	}
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	res := c.newDerived()
+	for k, v := range c.items {
+		res.items[k] = v
+	}
+	for k, incoming := range other.items {
+		if existing, ok := res.items[k]; ok {
+			res.items[k] = resolve(existing, incoming)
+		} else {
+			res.items[k] = incoming
+		}
+	}
+	return res
+}
+
+// MergeInto merges the items of other into this collection in place and returns it.
+// For keys present in both, the stored value is resolve(existing, incoming), where existing comes from this collection.
+// Both locks are held for the whole merge, acquired in the same order as CopyTo so that concurrent merges cannot deadlock,
+// so resolve must not call methods on either collection.
+func (c *Collection[K, V]) MergeInto(other *Collection[K, V], resolve func(existing, incoming V) V) *Collection[K, V] {
+	if other == c {
+		c.write(func(b *writeBatch[K, V]) {
+			for _, k := range c.keysUnlocked() {
+				v := c.items[k]
+				b.set(k, resolve(v, v))
+			}
+		})
+		return c
+	}
+	writePair(c, true, other, false, func(cb, _ *writeBatch[K, V]) {
+		for k, incoming := range other.items {
+			if existing, ok := cb.get(k); ok {
+				cb.set(k, resolve(existing, incoming))
+			} else {
+				cb.set(k, incoming)
+			}
+		}
+	})
+	return c
+}
+
 // Difference returns a new collection containing the items where the key is present in this collection but not the other.
 func (c *Collection[K, V]) Difference(other *Collection[K, any]) *Collection[K, V] {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	other.mu.RLock()
 	defer other.mu.RUnlock()
-	res := New[K, V]()
+	res := c.newDerived()
 	for k, v := range c.items {
 		if _, ok := other.items[k]; !ok {
 			res.items[k] = v
@@ -628,7 +1391,7 @@ func (c *Collection[K, V]) SymmetricDifference(other *Collection[K, V]) *Collect
 	defer c.mu.RUnlock()
 	other.mu.RLock()
 	defer other.mu.RUnlock()
-	res := New[K, V]()
+	res := c.newDerived()
 	for k, v := range c.items {
 		if _, ok := other.items[k]; !ok {
 			res.items[k] = v
@@ -642,6 +1405,50 @@ func (c *Collection[K, V]) SymmetricDifference(other *Collection[K, V]) *Collect
 	return res
 }
 
+// IsSubset checks if every key in this collection is also present in the other collection.
+func (c *Collection[K, V]) IsSubset(other *Collection[K, any]) bool {
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	for k := range c.items {
+		if _, ok := other.items[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset checks if every key in the other collection is also present in this collection.
+func (c *Collection[K, V]) IsSuperset(other *Collection[K, any]) bool {
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	for k := range other.items {
+		if _, ok := c.items[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint checks if this collection and the other collection share no keys.
+func (c *Collection[K, V]) IsDisjoint(other *Collection[K, any]) bool {
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	if len(c.items) <= len(other.items) {
+		for k := range c.items {
+			if _, ok := other.items[k]; ok {
+				return false
+			}
+		}
+		return true
+	}
+	for k := range other.items {
+		if _, ok := c.items[k]; ok {
+			return false
+		}
+	}
+	return true
+}
+
 // ToReversed returns a new collection with the items in reverse order.
 func (c *Collection[K, V]) ToReversed() *Collection[K, V] {
 	return c.Clone().Reverse()
@@ -663,6 +1470,192 @@ func (c *Collection[K, V]) ToJSON() ([]byte, error) {
 	return json.Marshal(pairs)
 }
 
+// lockPair acquires the locks of two distinct collections in address order so that concurrent operations
+// on the same pair cannot deadlock. writeA and writeB select the write lock instead of the read lock.
+// The collections may differ in value type; if a and b are the same collection, its lock is acquired once.
+// It returns a function that releases both locks.
+func lockPair[K comparable, V, W any](a *Collection[K, V], writeA bool, b *Collection[K, W], writeB bool) func() {
+	lock := func(mu *sync.RWMutex, write bool) func() {
+		if write {
+			mu.Lock()
+			return mu.Unlock
+		}
+		mu.RLock()
+		return mu.RUnlock
+	}
+	addrA, addrB := uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(b))
+	if addrA == addrB {
+		return lock(&a.mu, writeA || writeB)
+	}
+	if addrA > addrB {
+		unlockB := lock(&b.mu, writeB)
+		unlockA := lock(&a.mu, writeA)
+		return func() {
+			unlockA()
+			unlockB()
+		}
+	}
+	unlockA := lock(&a.mu, writeA)
+	unlockB := lock(&b.mu, writeB)
+	return func() {
+		unlockB()
+		unlockA()
+	}
+}
+
+// sortedKeysUnlocked returns the keys sorted by compare. The caller must hold the lock.
+func (c *Collection[K, V]) sortedKeysUnlocked(compare Comparator[K, V]) []K {
+	keys := c.keysUnlocked()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compare(c.items[keys[i]], c.items[keys[j]], keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+// selectNUnlocked returns a new collection with the n greatest items according to less, using a heap of size n
+// whose root is the least retained item. The caller must hold the lock.
+func (c *Collection[K, V]) selectNUnlocked(n int, less func(a, b K) bool) *Collection[K, V] {
+	res := c.newDerived()
+	if n <= 0 {
+		return res
+	}
+	h := &keyHeap[K]{less: less}
+	for k := range c.items {
+		if h.Len() < n {
+			heap.Push(h, k)
+		} else if less(h.keys[0], k) {
+			h.keys[0] = k
+			heap.Fix(h, 0)
+		}
+	}
+	for _, k := range h.keys {
+		res.items[k] = c.items[k]
+	}
+	return res
+}
+
+// keyHeap is a heap of keys ordered by less, implementing heap.Interface.
+type keyHeap[K comparable] struct {
+	keys []K
+	less func(a, b K) bool
+}
+
+func (h *keyHeap[K]) Len() int           { return len(h.keys) }
+func (h *keyHeap[K]) Less(i, j int) bool { return h.less(h.keys[i], h.keys[j]) }
+func (h *keyHeap[K]) Swap(i, j int)      { h.keys[i], h.keys[j] = h.keys[j], h.keys[i] }
+func (h *keyHeap[K]) Push(x any)         { h.keys = append(h.keys, x.(K)) }
+func (h *keyHeap[K]) Pop() any {
+	last := h.keys[len(h.keys)-1]
+	h.keys = h.keys[:len(h.keys)-1]
+	return last
+}
+
+// isZero reports whether v is the zero value of its type. A nil interface is zero.
+func isZero(v any) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || rv.IsZero()
+}
+
+// isNilPointer reports whether v is a nil pointer or a nil interface.
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil())
+}
+
+// valuePtr returns a pointer to v if ok, otherwise nil.
+func valuePtr[V any](v V, ok bool) *V {
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// sign normalizes a comparison result to -1, 0, or 1.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// keyIsString reports whether K is of a string kind, so its keys can be used as the names of an object or mapping.
+func keyIsString[K comparable]() bool {
+	return reflect.TypeFor[K]().Kind() == reflect.String
+}
+
+// snapshot returns copies of the keys and values of the collection, taken under the read lock.
+// The value at index i belongs to the key at index i.
+func (c *Collection[K, V]) snapshot() ([]K, []V) {
+	keys, values, _ := c.snapshotGen()
+	return keys, values
+}
+
+// snapshotGen is snapshot that also returns the write generation the copies were taken at.
+func (c *Collection[K, V]) snapshotGen() ([]K, []V, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, 0, len(c.items))
+	values := make([]V, 0, len(c.items))
+	for k, v := range c.items {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values, c.writeGen
+}
+
+// normalizeKey returns key as stored by the collection: unchanged unless the collection normalizes its keys.
+func (c *Collection[K, V]) normalizeKey(key K) K {
+	if c.keyNormalizer == nil {
+		return key
+	}
+	return c.keyNormalizer(key)
+}
+
+// newDerived returns an empty collection that normalizes keys as c does, for results built from the items of c.
+func (c *Collection[K, V]) newDerived() *Collection[K, V] {
+	res := New[K, V]()
+	res.keyNormalizer = c.keyNormalizer
+	return res
+}
+
+// randomCandidatesUnlocked returns the keys to choose random items from. With a custom source they are in
+// natural order, so that selections do not depend on map iteration order. The caller must hold the lock.
+func (c *Collection[K, V]) randomCandidatesUnlocked() []K {
+	keys := c.keysUnlocked()
+	c.randMu.Lock()
+	seeded := c.rng != nil
+	c.randMu.Unlock()
+	if seeded {
+		slices.SortFunc(keys, func(a, b K) int {
+			return compareNatural(a, b)
+		})
+	}
+	return keys
+}
+
+// randIntn returns a random int in [0, n) from the collection's source.
+func (c *Collection[K, V]) randIntn(n int) int {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	if c.rng != nil {
+		return c.rng.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+// randPerm returns a random permutation of [0, n) from the collection's source.
+func (c *Collection[K, V]) randPerm(n int) []int {
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	if c.rng != nil {
+		return c.rng.Perm(n)
+	}
+	return rand.Perm(n)
+}
+
 // keysUnlocked returns the keys in insertion order. (Go maps are unordered, so this is not guaranteed.)
 func (c *Collection[K, V]) keysUnlocked() []K {
 	keys := make([]K, 0, len(c.items))
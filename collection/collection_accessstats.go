@@ -0,0 +1,136 @@
+package collection
+
+import (
+	"sync"
+	"time"
+)
+
+// KeyStats holds the access statistics of a single key, as recorded after EnableAccessStats.
+// A key gets statistics once it is found or set; misses and deletes for a key that never had any are only
+// counted in CollectionStats, so lookups of arbitrary absent keys do not grow memory.
+type KeyStats struct {
+	Hits       uint64    // Get calls that found the key
+	Misses     uint64    // Get calls that did not find the key
+	Sets       uint64    // Set calls for the key
+	Deletes    uint64    // Delete calls for the key, whether or not it existed
+	LastAccess time.Time // time of the most recent Get, or zero
+	LastWrite  time.Time // time of the most recent Set or Delete, or zero
+}
+
+// CollectionStats holds the access statistics of a whole collection, as recorded after EnableAccessStats.
+type CollectionStats struct {
+	Hits    uint64
+	Misses  uint64
+	Sets    uint64
+	Deletes uint64
+}
+
+// HitRate returns the fraction of Get calls that found their key, or 0 if there were none.
+func (s CollectionStats) HitRate() float64 {
+	gets := s.Hits + s.Misses
+	if gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(gets)
+}
+
+// accessStats records per-key and aggregate access statistics.
+type accessStats[K comparable] struct {
+	mu     sync.Mutex
+	keys   map[K]*KeyStats
+	totals CollectionStats
+}
+
+func newAccessStats[K comparable]() *accessStats[K] {
+	return &accessStats[K]{keys: make(map[K]*KeyStats)}
+}
+
+// record counts a completed Get, Set or Delete of k. found reports whether k was present beforehand.
+func (s *accessStats[K]) record(op string, k K, found bool) {
+	at := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch op {
+	case opGet:
+		if found {
+			s.totals.Hits++
+		} else {
+			s.totals.Misses++
+		}
+	case opSet:
+		s.totals.Sets++
+	case opDelete:
+		s.totals.Deletes++
+	}
+
+	ks, ok := s.keys[k]
+	if !ok {
+		// Only keys that are present get per-key statistics
+		if !found && op != opSet {
+			return
+		}
+		ks = &KeyStats{}
+		s.keys[k] = ks
+	}
+	switch op {
+	case opGet:
+		if found {
+			ks.Hits++
+		} else {
+			ks.Misses++
+		}
+		ks.LastAccess = at
+	case opSet:
+		ks.Sets++
+		ks.LastWrite = at
+	case opDelete:
+		ks.Deletes++
+		ks.LastWrite = at
+	}
+}
+
+// EnableAccessStats starts recording Get hits and misses, Set calls and Delete calls for each key.
+// Calling it again keeps the statistics recorded so far.
+func (c *Collection[K, V]) EnableAccessStats() *Collection[K, V] {
+	c.stats.CompareAndSwap(nil, newAccessStats[K]())
+	return c
+}
+
+// AccessStats returns the statistics recorded for key. It returns zero stats if none were recorded.
+func (c *Collection[K, V]) AccessStats(key K) KeyStats {
+	s := c.stats.Load()
+	if s == nil {
+		return KeyStats{}
+	}
+	key = c.normalizeKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ks, ok := s.keys[key]; ok {
+		return *ks
+	}
+	return KeyStats{}
+}
+
+// GlobalStats returns the statistics recorded across all keys.
+func (c *Collection[K, V]) GlobalStats() CollectionStats {
+	s := c.stats.Load()
+	if s == nil {
+		return CollectionStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.totals
+}
+
+// ResetStats discards the recorded statistics. Recording continues if it was enabled.
+func (c *Collection[K, V]) ResetStats() *Collection[K, V] {
+	s := c.stats.Load()
+	if s == nil {
+		return c
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = make(map[K]*KeyStats)
+	s.totals = CollectionStats{}
+	return c
+}
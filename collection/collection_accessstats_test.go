@@ -0,0 +1,78 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionAccessStats tests the EnableAccessStats, AccessStats and GlobalStats methods
+func TestCollectionAccessStats(t *testing.T) {
+	c := collection.New[string, int]()
+
+	c.Set("a", 1)
+	c.Get("a")
+	if c.AccessStats("a") != (collection.KeyStats{}) || c.GlobalStats() != (collection.CollectionStats{}) {
+		t.Error("Nothing should be recorded before EnableAccessStats")
+	}
+
+	c.EnableAccessStats()
+	c.Set("a", 2)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Delete("a")
+
+	a := c.AccessStats("a")
+	if a.Hits != 2 || a.Misses != 0 || a.Sets != 1 || a.Deletes != 1 {
+		t.Errorf("Unexpected stats for a: %+v", a)
+	}
+	if a.LastAccess.IsZero() || a.LastWrite.IsZero() || a.LastWrite.Before(a.LastAccess) {
+		t.Errorf("Expected LastWrite (delete) at or after LastAccess, got %+v", a)
+	}
+	// Misses for keys that were never present are only counted in aggregate
+	if b := c.AccessStats("b"); b != (collection.KeyStats{}) {
+		t.Errorf("Expected no per-key stats for a missing key, got %+v", b)
+	}
+	if s := c.AccessStats("never"); s != (collection.KeyStats{}) {
+		t.Errorf("Expected zero stats for an untouched key, got %+v", s)
+	}
+
+	global := c.GlobalStats()
+	want := collection.CollectionStats{Hits: 2, Misses: 1, Sets: 1, Deletes: 1}
+	if global != want {
+		t.Errorf("Expected %+v, got %+v", want, global)
+	}
+	if rate := global.HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("Expected a hit rate of 2/3, got %f", rate)
+	}
+
+	// Enabling again keeps what was recorded
+	c.EnableAccessStats()
+	if c.GlobalStats() != want {
+		t.Error("EnableAccessStats should not reset recorded stats")
+	}
+
+	// A key that had stats keeps counting misses after it is deleted
+	c.Get("a")
+	if a := c.AccessStats("a"); a.Misses != 1 {
+		t.Errorf("Expected a miss for the deleted key a, got %+v", a)
+	}
+}
+
+// TestCollectionResetStats tests the ResetStats method
+func TestCollectionResetStats(t *testing.T) {
+	c := collection.New[string, int]().EnableAccessStats()
+	c.Set("a", 1)
+	c.Get("a")
+
+	c.ResetStats()
+	if c.GlobalStats() != (collection.CollectionStats{}) || c.AccessStats("a") != (collection.KeyStats{}) {
+		t.Error("ResetStats should clear all counters")
+	}
+
+	c.Get("a")
+	if c.AccessStats("a").Hits != 1 {
+		t.Error("Recording should continue after ResetStats")
+	}
+}
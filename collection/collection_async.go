@@ -0,0 +1,74 @@
+package collection
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// EachAsync executes fn for each element using at most concurrency goroutines, waits for all of them to finish,
+// and returns every non-nil error. A concurrency <= 0 defaults to runtime.GOMAXPROCS(0).
+// fn runs on a snapshot of the collection taken under the read lock, so it may safely call methods on the collection.
+func (c *Collection[K, V]) EachAsync(concurrency int, fn func(value V, key K) error) []error {
+	keys, values := c.snapshot()
+	var mu sync.Mutex
+	var errs []error
+	parallel(context.Background(), len(keys), concurrency, func(i int) {
+		if err := fn(values[i], keys[i]); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	})
+	return errs
+}
+
+// FilterAsync returns a new collection containing only the items for which fn returns true,
+// evaluating fn concurrently using at most concurrency goroutines. A concurrency <= 0 defaults to runtime.GOMAXPROCS(0).
+// fn runs on a snapshot of the collection taken under the read lock, so it may safely call methods on the collection.
+func (c *Collection[K, V]) FilterAsync(concurrency int, fn func(value V, key K) bool) *Collection[K, V] {
+	keys, values := c.snapshot()
+	pass := make([]bool, len(keys))
+	parallel(context.Background(), len(keys), concurrency, func(i int) {
+		pass[i] = fn(values[i], keys[i])
+	})
+	res := New[K, V]()
+	for i, k := range keys {
+		if pass[i] {
+			res.items[k] = values[i]
+		}
+	}
+	return res
+}
+
+// parallel calls fn for every index in [0, n) using a pool of at most concurrency goroutines and waits for them to finish.
+// No further indexes are dispatched once ctx is done. A concurrency <= 0 defaults to runtime.GOMAXPROCS(0).
+func parallel(ctx context.Context, n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
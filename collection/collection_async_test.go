@@ -0,0 +1,104 @@
+package collection_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionEachAsync tests the EachAsync method
+func TestCollectionEachAsync(t *testing.T) {
+	c := collection.New[int, int]()
+
+	if errs := c.EachAsync(4, func(value int, key int) error { return nil }); len(errs) != 0 {
+		t.Errorf("EachAsync on empty collection should return no errors, got %v", errs)
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	var sum atomic.Int64
+	errs := c.EachAsync(8, func(value int, key int) error {
+		sum.Add(int64(value))
+		return nil
+	})
+	if errs != nil {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if sum.Load() != 4950 {
+		t.Errorf("Every entry should be visited once, got sum %d", sum.Load())
+	}
+
+	// Errors are collected
+	errOdd := errors.New("odd")
+	errs = c.EachAsync(0, func(value int, key int) error {
+		if value%2 == 1 {
+			return errOdd
+		}
+		return nil
+	})
+	if len(errs) != 50 {
+		t.Errorf("Expected 50 errors, got %d", len(errs))
+	}
+
+	// The callback may use the collection
+	c.EachAsync(4, func(value int, key int) error {
+		c.Has(key)
+		return nil
+	})
+}
+
+// TestCollectionEachAsyncConcurrency tests that EachAsync bounds the number of goroutines
+func TestCollectionEachAsyncConcurrency(t *testing.T) {
+	c := collection.New[int, int]()
+	for i := 0; i < 20; i++ {
+		c.Set(i, i)
+	}
+
+	var running, peak atomic.Int32
+	c.EachAsync(3, func(value int, key int) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	})
+	if peak.Load() > 3 {
+		t.Errorf("At most 3 goroutines should run at once, saw %d", peak.Load())
+	}
+}
+
+// TestCollectionFilterAsync tests the FilterAsync method
+func TestCollectionFilterAsync(t *testing.T) {
+	c := collection.New[int, int]()
+
+	if result := c.FilterAsync(4, func(value int, key int) bool { return true }); result.Size() != 0 {
+		t.Errorf("FilterAsync on empty collection should be empty, got size %d", result.Size())
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	result := c.FilterAsync(8, func(value int, key int) bool {
+		return value%3 == 0
+	})
+	if result.Size() != 34 {
+		t.Errorf("Expected 34 entries divisible by 3, got %d", result.Size())
+	}
+	if !result.HasAll(0, 3, 99) || result.Has(1) {
+		t.Error("FilterAsync should keep exactly the passing entries")
+	}
+	if c.Size() != 100 {
+		t.Error("Original collection should be unchanged")
+	}
+}
@@ -0,0 +1,87 @@
+package collection
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Bag is a multiset that counts how many times each item has been added, backed by a Collection.
+// Items whose count drops to zero are removed. It is safe for concurrent use.
+type Bag[K comparable] struct {
+	items *Collection[K, int]
+}
+
+// NewBag creates a new empty Bag.
+func NewBag[K comparable]() *Bag[K] {
+	return &Bag[K]{items: New[K, int]()}
+}
+
+// Add increments the count of item.
+func (b *Bag[K]) Add(item K) *Bag[K] {
+	b.items.mu.Lock()
+	defer b.items.mu.Unlock()
+	b.items.items[item]++
+	return b
+}
+
+// Remove decrements the count of item, removing it once its count reaches zero.
+// Returns false if the item was not in the bag.
+func (b *Bag[K]) Remove(item K) bool {
+	b.items.mu.Lock()
+	defer b.items.mu.Unlock()
+	count, ok := b.items.items[item]
+	if !ok {
+		return false
+	}
+	if count <= 1 {
+		delete(b.items.items, item)
+	} else {
+		b.items.items[item] = count - 1
+	}
+	return true
+}
+
+// Count returns how many times item is in the bag.
+func (b *Bag[K]) Count(item K) int {
+	count, _ := b.items.Get(item)
+	return count
+}
+
+// Contains reports whether item is in the bag at least once.
+func (b *Bag[K]) Contains(item K) bool {
+	return b.items.Has(item)
+}
+
+// Total returns the sum of the counts of all items.
+func (b *Bag[K]) Total() int {
+	b.items.mu.RLock()
+	defer b.items.mu.RUnlock()
+	total := 0
+	for _, count := range b.items.items {
+		total += count
+	}
+	return total
+}
+
+// Items returns the distinct items in the bag.
+func (b *Bag[K]) Items() []K {
+	return b.items.Keys()
+}
+
+// MostCommon returns up to n items with the highest counts, most common first.
+// Items with equal counts are in ascending natural order.
+func (b *Bag[K]) MostCommon(n int) []K {
+	if n <= 0 {
+		return []K{}
+	}
+	b.items.mu.RLock()
+	defer b.items.mu.RUnlock()
+	keys := b.items.keysUnlocked()
+	slices.SortFunc(keys, func(x, y K) int {
+		if c := cmp.Compare(b.items.items[y], b.items.items[x]); c != 0 {
+			return c
+		}
+		return compareNatural(x, y)
+	})
+	return keys[:min(n, len(keys))]
+}
@@ -0,0 +1,68 @@
+package collection_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestBag tests counting in Bag
+func TestBag(t *testing.T) {
+	b := collection.NewBag[string]()
+	for _, word := range []string{"a", "b", "a", "c", "a", "b"} {
+		b.Add(word)
+	}
+
+	if b.Count("a") != 3 || b.Count("b") != 2 || b.Count("c") != 1 {
+		t.Errorf("Unexpected counts a=%d b=%d c=%d", b.Count("a"), b.Count("b"), b.Count("c"))
+	}
+	if b.Count("missing") != 0 {
+		t.Error("Count of a missing item should be 0")
+	}
+	if b.Total() != 6 {
+		t.Errorf("Expected total 6, got %d", b.Total())
+	}
+	if !b.Contains("c") || b.Contains("missing") {
+		t.Error("Contains should report membership")
+	}
+
+	items := b.Items()
+	sort.Strings(items)
+	if !reflect.DeepEqual(items, []string{"a", "b", "c"}) {
+		t.Errorf("Expected items [a b c], got %v", items)
+	}
+
+	if !b.Remove("c") {
+		t.Error("Remove should return true for an item in the bag")
+	}
+	if b.Contains("c") {
+		t.Error("An item should be removed once its count reaches zero")
+	}
+	if b.Remove("c") {
+		t.Error("Remove should return false for an item not in the bag")
+	}
+	b.Remove("a")
+	if b.Count("a") != 2 || b.Total() != 4 {
+		t.Errorf("Expected a=2 and total 4, got a=%d total=%d", b.Count("a"), b.Total())
+	}
+}
+
+// TestBagMostCommon tests the MostCommon method
+func TestBagMostCommon(t *testing.T) {
+	b := collection.NewBag[string]()
+	for _, word := range []string{"x", "y", "y", "z", "z", "z", "w", "w"} {
+		b.Add(word)
+	}
+
+	if got := b.MostCommon(3); !reflect.DeepEqual(got, []string{"z", "w", "y"}) {
+		t.Errorf("Expected [z w y], got %v", got)
+	}
+	if got := b.MostCommon(10); len(got) != 4 {
+		t.Errorf("Expected every item when n exceeds the bag size, got %v", got)
+	}
+	if got := b.MostCommon(0); len(got) != 0 {
+		t.Errorf("Expected no items for n = 0, got %v", got)
+	}
+}
@@ -0,0 +1,193 @@
+package collection
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// ErrCapacityExceeded is returned when an item cannot be stored because a collection is full.
+var ErrCapacityExceeded = errors.New("collection: capacity exceeded")
+
+// EvictionPolicy selects which entry a BoundedCollection evicts when it is full.
+type EvictionPolicy[K comparable] interface {
+	// Victim returns the key to evict from keys, which are ordered oldest first and must not be modified.
+	// Returning false means no entry can be evicted.
+	Victim(keys []K) (K, bool)
+}
+
+// EvictionPolicyFunc adapts a function to the EvictionPolicy interface.
+type EvictionPolicyFunc[K comparable] func(keys []K) (K, bool)
+
+// Victim calls f(keys).
+func (f EvictionPolicyFunc[K]) Victim(keys []K) (K, bool) {
+	return f(keys)
+}
+
+// EvictOldest returns a policy that evicts the entry inserted first.
+func EvictOldest[K comparable]() EvictionPolicy[K] {
+	return EvictionPolicyFunc[K](func(keys []K) (K, bool) {
+		if len(keys) == 0 {
+			var zero K
+			return zero, false
+		}
+		return keys[0], true
+	})
+}
+
+// EvictRandom returns a policy that evicts a random entry.
+func EvictRandom[K comparable]() EvictionPolicy[K] {
+	return EvictionPolicyFunc[K](func(keys []K) (K, bool) {
+		if len(keys) == 0 {
+			var zero K
+			return zero, false
+		}
+		return keys[rand.Intn(len(keys))], true
+	})
+}
+
+// BoundedCollection is a map-like structure that holds at most a fixed number of items,
+// evicting entries according to its EvictionPolicy when full.
+// It is safe for concurrent use.
+type BoundedCollection[K comparable, V any] struct {
+	mu          sync.RWMutex
+	items       map[K]V
+	order       []K
+	maxCapacity int
+	policy      EvictionPolicy[K]
+	onEvict     func(key K, value V)
+}
+
+// NewBounded creates a new BoundedCollection holding at most maxCapacity items.
+// A nil policy defaults to EvictOldest.
+func NewBounded[K comparable, V any](maxCapacity int, policy EvictionPolicy[K]) *BoundedCollection[K, V] {
+	if policy == nil {
+		policy = EvictOldest[K]()
+	}
+	return &BoundedCollection[K, V]{
+		items:       make(map[K]V),
+		maxCapacity: maxCapacity,
+		policy:      policy,
+	}
+}
+
+// OnEvict registers a function called for each evicted entry, replacing any previous one.
+// It is called after the lock is released.
+func (c *BoundedCollection[K, V]) OnEvict(fn func(key K, value V)) *BoundedCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = fn
+	return c
+}
+
+// Set adds or updates an item, evicting entries first if the collection is full.
+// Returns ErrCapacityExceeded if no room could be made.
+func (c *BoundedCollection[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	if _, ok := c.items[key]; ok {
+		c.items[key] = value
+		c.mu.Unlock()
+		return nil
+	}
+
+	var evictedKeys []K
+	var evictedValues []V
+	for len(c.items) >= c.maxCapacity {
+		victim, ok := c.policy.Victim(c.order)
+		if !ok {
+			break
+		}
+		v, exists := c.items[victim]
+		if !exists {
+			break
+		}
+		c.deleteUnlocked(victim)
+		evictedKeys = append(evictedKeys, victim)
+		evictedValues = append(evictedValues, v)
+	}
+
+	var err error
+	if len(c.items) < c.maxCapacity {
+		c.items[key] = value
+		c.order = append(c.order, key)
+	} else {
+		err = ErrCapacityExceeded
+	}
+	onEvict := c.onEvict
+	c.mu.Unlock()
+
+	if onEvict != nil {
+		for i, k := range evictedKeys {
+			onEvict(k, evictedValues[i])
+		}
+	}
+	return err
+}
+
+// Get retrieves an item from the collection.
+func (c *BoundedCollection[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	val, ok := c.items[key]
+	return val, ok
+}
+
+// Has checks if a key exists in the collection.
+func (c *BoundedCollection[K, V]) Has(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.items[key]
+	return ok
+}
+
+// Delete removes an item from the collection.
+func (c *BoundedCollection[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; !ok {
+		return false
+	}
+	c.deleteUnlocked(key)
+	return true
+}
+
+// Clear removes all items from the collection.
+func (c *BoundedCollection[K, V]) Clear() *BoundedCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]V)
+	c.order = nil
+	return c
+}
+
+// Size returns the number of items in the collection.
+func (c *BoundedCollection[K, V]) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// Capacity returns the maximum number of items the collection can hold.
+func (c *BoundedCollection[K, V]) Capacity() int {
+	return c.maxCapacity
+}
+
+// Keys returns all keys in insertion order, oldest first.
+func (c *BoundedCollection[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, len(c.order))
+	copy(keys, c.order)
+	return keys
+}
+
+// deleteUnlocked removes key from the items and the insertion order. The caller must hold the write lock.
+func (c *BoundedCollection[K, V]) deleteUnlocked(key K) {
+	delete(c.items, key)
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package collection_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestBoundedCollectionEvictOldest tests eviction of the oldest entry
+func TestBoundedCollectionEvictOldest(t *testing.T) {
+	c := collection.NewBounded[string, int](2, collection.EvictOldest[string]())
+
+	var evicted []string
+	c.OnEvict(func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+
+	for i, k := range []string{"a", "b", "c"} {
+		if err := c.Set(k, i); err != nil {
+			t.Fatalf("Set should not fail, got %v", err)
+		}
+	}
+
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+	if c.Has("a") {
+		t.Error("Oldest entry should have been evicted")
+	}
+	if !reflect.DeepEqual(evicted, []string{"a"}) {
+		t.Errorf("Expected eviction of [a], got %v", evicted)
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"b", "c"}) {
+		t.Errorf("Expected keys [b c], got %v", keys)
+	}
+
+	// Updating an existing key does not evict
+	if err := c.Set("b", 10); err != nil {
+		t.Errorf("Updating existing key should not fail, got %v", err)
+	}
+	if len(evicted) != 1 {
+		t.Errorf("Updating existing key should not evict, got evictions %v", evicted)
+	}
+	if val, _ := c.Get("b"); val != 10 {
+		t.Errorf("Expected updated value 10, got %d", val)
+	}
+}
+
+// TestBoundedCollectionEvictRandom tests random eviction
+func TestBoundedCollectionEvictRandom(t *testing.T) {
+	c := collection.NewBounded[int, int](5, collection.EvictRandom[int]())
+	for i := 0; i < 20; i++ {
+		if err := c.Set(i, i); err != nil {
+			t.Fatalf("Set should not fail, got %v", err)
+		}
+	}
+	if c.Size() != 5 {
+		t.Errorf("Expected size 5, got %d", c.Size())
+	}
+	if !c.Has(19) {
+		t.Error("Most recently inserted entry should be present")
+	}
+}
+
+// TestBoundedCollectionCapacityExceeded tests Set failing when no room can be made
+func TestBoundedCollectionCapacityExceeded(t *testing.T) {
+	never := collection.EvictionPolicyFunc[string](func(keys []string) (string, bool) {
+		return "", false
+	})
+	c := collection.NewBounded[string, int](1, never)
+
+	if err := c.Set("a", 1); err != nil {
+		t.Fatalf("First Set should succeed, got %v", err)
+	}
+	if err := c.Set("b", 2); !errors.Is(err, collection.ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded, got %v", err)
+	}
+	if c.Has("b") {
+		t.Error("Rejected entry should not be stored")
+	}
+
+	zero := collection.NewBounded[string, int](0, nil)
+	if err := zero.Set("a", 1); !errors.Is(err, collection.ErrCapacityExceeded) {
+		t.Errorf("Zero-capacity collection should reject entries, got %v", err)
+	}
+}
+
+// TestBoundedCollectionDelete tests Delete and Clear
+func TestBoundedCollectionDelete(t *testing.T) {
+	c := collection.NewBounded[string, int](3, nil)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	if !c.Delete("a") {
+		t.Error("Deleting existing key should return true")
+	}
+	if c.Delete("a") {
+		t.Error("Deleting missing key should return false")
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"b"}) {
+		t.Errorf("Expected keys [b], got %v", keys)
+	}
+
+	c.Clear()
+	if c.Size() != 0 || len(c.Keys()) != 0 {
+		t.Error("Collection should be empty after Clear")
+	}
+	if c.Capacity() != 3 {
+		t.Errorf("Expected capacity 3, got %d", c.Capacity())
+	}
+}
@@ -0,0 +1,14 @@
+package collection
+
+import "strings"
+
+// NewCaseInsensitive creates a new Collection whose keys are converted to lower case before they are stored
+// or looked up, so "User", "user" and "USER" refer to the same item and Keys returns lower-case keys.
+// Keys are normalized by every method that stores items, including bulk writes such as MergeInto, CopyTo,
+// Patch, AtomicApply and the decoders, and by every lookup. When several stored keys differ only in case,
+// the one written last wins.
+func NewCaseInsensitive[V any]() *Collection[string, V] {
+	c := New[string, V]()
+	c.keyNormalizer = strings.ToLower
+	return c
+}
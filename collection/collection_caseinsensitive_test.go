@@ -0,0 +1,134 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNewCaseInsensitive tests case-normalized keys
+func TestNewCaseInsensitive(t *testing.T) {
+	c := collection.NewCaseInsensitive[int]()
+	c.Set("User", 1).Set("USER", 2)
+
+	if c.Size() != 1 {
+		t.Errorf("Expected a single entry, got %d", c.Size())
+	}
+	for _, key := range []string{"user", "User", "USER"} {
+		if !c.Has(key) {
+			t.Errorf("Has(%q) should be true", key)
+		}
+		if val, _ := c.Get(key); val != 2 {
+			t.Errorf("Get(%q): expected 2, got %d", key, val)
+		}
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"user"}) {
+		t.Errorf("Expected lower-case keys [user], got %v", keys)
+	}
+	if !c.HasAll("USER", "user") || !c.HasAny("uSeR") {
+		t.Error("HasAll and HasAny should ignore case")
+	}
+
+	if val := c.SetDefault("Admin", 5); val != 5 || !c.Has("admin") {
+		t.Error("SetDefault should store the lower-case key")
+	}
+	if !c.Delete("ADMIN") || c.Has("admin") {
+		t.Error("Delete should ignore case")
+	}
+}
+
+// TestNewCaseInsensitiveBulkWrites tests that methods writing many keys at once normalize them
+func TestNewCaseInsensitiveBulkWrites(t *testing.T) {
+	expectUser := func(desc string, c *collection.Collection[string, int], want int) {
+		t.Helper()
+		if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"user"}) {
+			t.Errorf("%s: expected keys [user], got %v", desc, keys)
+		}
+		if val, _ := c.Get("USER"); val != want {
+			t.Errorf("%s: expected %d, got %d", desc, want, val)
+		}
+	}
+
+	c := collection.NewCaseInsensitive[int]().Set("user", 1)
+	c.MergeInto(collection.New[string, int]().Set("User", 2), func(existing, incoming int) int { return existing + incoming })
+	expectUser("MergeInto", c, 3)
+
+	c = collection.NewCaseInsensitive[int]()
+	collection.New[string, int]().Set("User", 1).CopyTo(c)
+	expectUser("CopyTo", c, 1)
+
+	c = collection.NewCaseInsensitive[int]()
+	collection.New[string, int]().Set("USER", 2).MoveEntry("USER", c)
+	expectUser("MoveEntry", c, 2)
+
+	c = collection.NewCaseInsensitive[int]().Set("user", 1)
+	collection.Patch(c, collection.DiffResult[string, int]{Added: collection.New[string, int]().Set("User", 4)})
+	expectUser("Patch", c, 4)
+
+	c = collection.NewCaseInsensitive[int]()
+	if err := c.UnmarshalText([]byte(`{"User":1,"user":2}`)); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	expectUser("UnmarshalText", c, 2)
+
+	c = collection.NewCaseInsensitive[int]()
+	if _, err := c.ReadFrom(strings.NewReader(`[["user",1],["USER",5]]`)); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	expectUser("ReadFrom", c, 5)
+
+	c = collection.NewCaseInsensitive[int]()
+	if err := c.ImportFrom(strings.NewReader(`{"User":6}`), "json", nil, nil); err != nil {
+		t.Fatalf("ImportFrom failed: %v", err)
+	}
+	expectUser("ImportFrom", c, 6)
+
+	c = collection.NewCaseInsensitive[int]().Set("user", 1)
+	err := c.AtomicApply(func(work *collection.Collection[string, int]) error {
+		work.Set("USER", 7)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AtomicApply failed: %v", err)
+	}
+	expectUser("AtomicApply", c, 7)
+
+	c = collection.NewCaseInsensitive[int]()
+	c.WithWriteLock(func(tx *collection.LockedWriter[string, int]) {
+		tx.Set("User", 8)
+		if !tx.Has("USER") {
+			t.Error("WithWriteLock: Has should ignore case")
+		}
+	})
+	expectUser("WithWriteLock", c, 8)
+}
+
+// TestNewCaseInsensitiveDerived tests that copies of the collection keep normalizing keys
+func TestNewCaseInsensitiveDerived(t *testing.T) {
+	c := collection.NewCaseInsensitive[int]().Set("user", 1)
+	deep, err := c.DeepClone()
+	if err != nil {
+		t.Fatalf("DeepClone failed: %v", err)
+	}
+	copies := map[string]*collection.Collection[string, int]{
+		"Clone":      c.Clone(),
+		"DeepClone":  deep,
+		"CloneWhere": c.CloneWhere(func(int, string) bool { return true }),
+		"Filter": c.Filter(func(int, string, *collection.Collection[string, int]) bool {
+			return true
+		}),
+		"ToSorted": c.ToSorted(func(a, b int, _, _ string) int { return a - b }),
+		"Thaw":     c.Freeze().Thaw(),
+	}
+	for name, copied := range copies {
+		copied.Set("USER", 2)
+		if keys := copied.Keys(); len(keys) != 1 || keys[0] != "user" {
+			t.Errorf("%s: expected the single key user, got %v", name, keys)
+		}
+	}
+	if !c.Freeze().Has("USER") {
+		t.Error("Freeze: Has should ignore case")
+	}
+}
@@ -0,0 +1,110 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrChangelogDisabled is returned by Revert when the changelog is not enabled.
+var ErrChangelogDisabled = errors.New("collection: changelog is not enabled")
+
+// ChangeOp identifies the mutation recorded by a ChangeRecord. It uses the EventType values EventSet, EventDelete, and EventClear.
+type ChangeOp = EventType
+
+// ChangeRecord describes a single mutation recorded by the changelog.
+// OldValue is nil if the key was absent before the mutation; NewValue is nil if the key is absent after it.
+// Clear is recorded as a single record with a zero Key and nil values.
+type ChangeRecord[K comparable, V any] struct {
+	Timestamp time.Time
+	Op        ChangeOp
+	Key       K
+	OldValue  *V
+	NewValue  *V
+
+	// cleared holds the items removed by a Clear, so that Revert can restore them. It is never modified.
+	cleared map[K]V
+}
+
+// EnableChangelog starts recording every change in the changelog: each stored value as a Set, each removed
+// item as a Delete, and each Clear as a single record, whichever method made the change.
+func (c *Collection[K, V]) EnableChangelog() *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changelogEnabled = true
+	return c
+}
+
+// DisableChangelog stops recording mutations and discards the changelog.
+func (c *Collection[K, V]) DisableChangelog() *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changelogEnabled = false
+	c.changelog = nil
+	return c
+}
+
+// Changelog returns the mutations recorded since the changelog was enabled or last cleared, oldest first.
+func (c *Collection[K, V]) Changelog() []ChangeRecord[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	records := make([]ChangeRecord[K, V], len(c.changelog))
+	copy(records, c.changelog)
+	return records
+}
+
+// ClearChangelog discards the recorded mutations without disabling the changelog.
+func (c *Collection[K, V]) ClearChangelog() *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changelog = nil
+	return c
+}
+
+// recordChangeUnlocked appends a record to the changelog.
+// The caller must hold the write lock and should only call it while the changelog is enabled.
+func (c *Collection[K, V]) recordChangeUnlocked(op ChangeOp, key K, oldValue, newValue *V) {
+	c.changelog = append(c.changelog, ChangeRecord[K, V]{
+		Timestamp: time.Now(),
+		Op:        op,
+		Key:       key,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	})
+}
+
+// Revert undoes the last n recorded mutations, newest first, and removes them from the changelog.
+// Reverting a Clear restores every cleared item. All n mutations are undone under a single write lock
+// without new changelog records or middleware; watchers and hooks are notified of the restored items.
+// Returns ErrChangelogDisabled if the changelog is not enabled, or an error if fewer than n mutations are recorded.
+func (c *Collection[K, V]) Revert(n int) error {
+	var err error
+	c.write(func(b *writeBatch[K, V]) {
+		b.reverting = true
+		if !c.changelogEnabled {
+			err = ErrChangelogDisabled
+			return
+		}
+		if n > len(c.changelog) {
+			err = fmt.Errorf("collection: cannot revert %d changes, only %d recorded", n, len(c.changelog))
+			return
+		}
+		for i := len(c.changelog) - 1; i >= len(c.changelog)-n; i-- {
+			record := c.changelog[i]
+			switch record.Op {
+			case EventSet, EventDelete:
+				if record.OldValue == nil {
+					b.remove(record.Key)
+				} else {
+					b.store(record.Key, *record.OldValue)
+				}
+			case EventClear:
+				for k, v := range record.cleared {
+					b.store(k, v)
+				}
+			}
+		}
+		c.changelog = c.changelog[:len(c.changelog)-max(n, 0)]
+	})
+	return err
+}
@@ -0,0 +1,117 @@
+package collection_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionChangelog tests recording mutations
+func TestCollectionChangelog(t *testing.T) {
+	c := collection.New[string, int]()
+
+	c.Set("ignored", 0)
+	if len(c.Changelog()) != 0 {
+		t.Error("Mutations before EnableChangelog should not be recorded")
+	}
+
+	c.EnableChangelog()
+	c.Set("a", 1).Set("a", 2)
+	c.Delete("a")
+	c.Delete("missing")
+	c.Clear()
+
+	log := c.Changelog()
+	if len(log) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(log))
+	}
+
+	if log[0].Op != collection.EventSet || log[0].Key != "a" || log[0].OldValue != nil || *log[0].NewValue != 1 {
+		t.Errorf("Unexpected insert record %+v", log[0])
+	}
+	if *log[1].OldValue != 1 || *log[1].NewValue != 2 {
+		t.Errorf("Update record should carry old and new values, got %+v", log[1])
+	}
+	if log[2].Op != collection.EventDelete || *log[2].OldValue != 2 || log[2].NewValue != nil {
+		t.Errorf("Unexpected delete record %+v", log[2])
+	}
+	if log[3].Op != collection.EventClear {
+		t.Errorf("Expected clear record, got %+v", log[3])
+	}
+	for i := 1; i < len(log); i++ {
+		if log[i].Timestamp.Before(log[i-1].Timestamp) {
+			t.Error("Records should be in chronological order")
+		}
+	}
+
+	// The returned slice is a copy
+	log[0].Key = "changed"
+	if c.Changelog()[0].Key != "a" {
+		t.Error("Changelog should return a copy of the records")
+	}
+}
+
+// TestCollectionChangelogClearAndDisable tests ClearChangelog and DisableChangelog
+func TestCollectionChangelogClearAndDisable(t *testing.T) {
+	c := collection.New[string, int]().EnableChangelog()
+	c.Set("a", 1)
+
+	c.ClearChangelog()
+	if len(c.Changelog()) != 0 {
+		t.Error("ClearChangelog should discard records")
+	}
+	c.Set("b", 2)
+	if len(c.Changelog()) != 1 {
+		t.Error("Changelog should keep recording after ClearChangelog")
+	}
+
+	c.DisableChangelog()
+	if len(c.Changelog()) != 0 {
+		t.Error("DisableChangelog should discard records")
+	}
+	c.Set("c", 3)
+	if len(c.Changelog()) != 0 {
+		t.Error("Mutations after DisableChangelog should not be recorded")
+	}
+}
+
+// TestCollectionRevert tests undoing recorded mutations
+func TestCollectionRevert(t *testing.T) {
+	c := collection.New[string, int]()
+	if err := c.Revert(1); !errors.Is(err, collection.ErrChangelogDisabled) {
+		t.Errorf("Expected ErrChangelogDisabled, got %v", err)
+	}
+
+	c.Set("a", 1).EnableChangelog()
+	c.Set("a", 2).Set("b", 3)
+	c.Delete("a")
+	c.Clear()
+	c.Set("c", 4)
+
+	if err := c.Revert(10); err == nil {
+		t.Error("Revert should fail when fewer changes are recorded")
+	}
+	if c.Size() != 1 || len(c.Changelog()) != 5 {
+		t.Error("A failed Revert should not change anything")
+	}
+
+	// Undo Set("c") and Clear
+	if err := c.Revert(2); err != nil {
+		t.Fatalf("Revert returned error: %v", err)
+	}
+	if c.Size() != 1 || c.Has("c") || !c.Has("b") {
+		t.Errorf("Expected only b after reverting Clear, got %v", c.Keys())
+	}
+
+	// Undo Delete("a"), Set("b") and Set("a", 2)
+	if err := c.Revert(3); err != nil {
+		t.Fatalf("Revert returned error: %v", err)
+	}
+	if val, _ := c.Get("a"); val != 1 || c.Size() != 1 {
+		t.Errorf("Expected the state before the changelog (a=1), got %v", c.Keys())
+	}
+	if len(c.Changelog()) != 0 {
+		t.Errorf("Reverted changes should be removed from the changelog, got %d", len(c.Changelog()))
+	}
+}
@@ -0,0 +1,34 @@
+package collection
+
+import "context"
+
+// NewFromChannel creates a new Collection from the entries received on ch, until ch is closed or ctx is done.
+// If ctx is done first, it returns the entries received so far together with ctx.Err().
+// Later entries for a key replace earlier ones.
+func NewFromChannel[K comparable, V any](ch <-chan Entry[K, V], ctx context.Context) (*Collection[K, V], error) {
+	c := New[K, V]()
+	for {
+		select {
+		case <-ctx.Done():
+			return c, ctx.Err()
+		case entry, ok := <-ch:
+			if !ok {
+				return c, nil
+			}
+			c.items[entry.Key] = entry.Value
+		}
+	}
+}
+
+// DrainIntoChannel returns a closed channel holding a snapshot of the items, in no particular order.
+// The channel is buffered to the size of the snapshot, so abandoning it early leaks nothing.
+func DrainIntoChannel[K comparable, V any](c *Collection[K, V]) <-chan Entry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ch := make(chan Entry[K, V], len(c.items))
+	for k, v := range c.items {
+		ch <- Entry[K, V]{Key: k, Value: v}
+	}
+	close(ch)
+	return ch
+}
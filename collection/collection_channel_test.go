@@ -0,0 +1,63 @@
+package collection_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNewFromChannel tests the NewFromChannel function
+func TestNewFromChannel(t *testing.T) {
+	ch := make(chan collection.Entry[string, int], 3)
+	ch <- collection.Entry[string, int]{Key: "a", Value: 1}
+	ch <- collection.Entry[string, int]{Key: "b", Value: 2}
+	ch <- collection.Entry[string, int]{Key: "a", Value: 3}
+	close(ch)
+
+	c, err := collection.NewFromChannel(ch, context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Size() != 2 {
+		t.Errorf("Expected 2 items, got %d", c.Size())
+	}
+	if val, _ := c.Get("a"); val != 3 {
+		t.Errorf("Expected the later entry a = 3, got %d", val)
+	}
+
+	// An open channel is read until the context is done
+	open := make(chan collection.Entry[string, int])
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		open <- collection.Entry[string, int]{Key: "x", Value: 1}
+		cancel()
+	}()
+	c, err = collection.NewFromChannel(open, ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if c == nil || !c.Has("x") {
+		t.Error("Expected the entries received before cancellation to be returned")
+	}
+}
+
+// TestDrainIntoChannel tests the DrainIntoChannel function
+func TestDrainIntoChannel(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	ch := collection.DrainIntoChannel(c)
+	c.Set("c", 3) // the channel holds a snapshot
+
+	round, err := collection.NewFromChannel(ch, context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if round.Size() != 2 || round.Has("c") {
+		t.Errorf("Expected the snapshot a and b, got keys %v", round.Keys())
+	}
+	if val, _ := round.Get("b"); val != 2 {
+		t.Errorf("Expected b = 2, got %d", val)
+	}
+}
@@ -0,0 +1,121 @@
+package collection
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextualCollection wraps a Collection so that iteration stops once a context is canceled.
+// Operations check the context between items and return the partial result together with ctx.Err().
+type ContextualCollection[K comparable, V any] struct {
+	ctx        context.Context
+	collection *Collection[K, V]
+}
+
+// WithContext wraps c so that its iteration operations are bound to ctx.
+func WithContext[K comparable, V any](ctx context.Context, c *Collection[K, V]) *ContextualCollection[K, V] {
+	return &ContextualCollection[K, V]{ctx: ctx, collection: c}
+}
+
+// Collection returns the wrapped collection.
+func (cc *ContextualCollection[K, V]) Collection() *Collection[K, V] {
+	return cc.collection
+}
+
+// Each executes fn for each element until the context is canceled. Returns ctx.Err() if iteration was cut short.
+func (cc *ContextualCollection[K, V]) Each(fn func(value V, key K, collection *Collection[K, V])) error {
+	keys, values := cc.collection.snapshot()
+	for i, k := range keys {
+		if err := cc.ctx.Err(); err != nil {
+			return err
+		}
+		fn(values[i], k, cc.collection)
+	}
+	return nil
+}
+
+// EachAsync executes fn concurrently like Collection.EachAsync, but stops dispatching items once the context is canceled.
+// Returns the errors from fn and ctx.Err() if iteration was cut short.
+func (cc *ContextualCollection[K, V]) EachAsync(concurrency int, fn func(value V, key K) error) ([]error, error) {
+	keys, values := cc.collection.snapshot()
+	var mu sync.Mutex
+	var errs []error
+	parallel(cc.ctx, len(keys), concurrency, func(i int) {
+		if cc.ctx.Err() != nil {
+			return
+		}
+		if err := fn(values[i], keys[i]); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	})
+	return errs, cc.ctx.Err()
+}
+
+// Filter returns a new collection containing the items for which fn returns true, evaluated until the context is canceled.
+// Returns the partial result and ctx.Err() if iteration was cut short.
+func (cc *ContextualCollection[K, V]) Filter(fn func(value V, key K, collection *Collection[K, V]) bool) (*Collection[K, V], error) {
+	keys, values := cc.collection.snapshot()
+	res := New[K, V]()
+	for i, k := range keys {
+		if err := cc.ctx.Err(); err != nil {
+			return res, err
+		}
+		if fn(values[i], k, cc.collection) {
+			res.items[k] = values[i]
+		}
+	}
+	return res, nil
+}
+
+// FilterAsync evaluates fn concurrently like Collection.FilterAsync, but stops dispatching items once the context is canceled.
+// Returns the partial result and ctx.Err() if evaluation was cut short.
+func (cc *ContextualCollection[K, V]) FilterAsync(concurrency int, fn func(value V, key K) bool) (*Collection[K, V], error) {
+	keys, values := cc.collection.snapshot()
+	pass := make([]bool, len(keys))
+	parallel(cc.ctx, len(keys), concurrency, func(i int) {
+		if cc.ctx.Err() != nil {
+			return
+		}
+		pass[i] = fn(values[i], keys[i])
+	})
+	res := New[K, V]()
+	for i, k := range keys {
+		if pass[i] {
+			res.items[k] = values[i]
+		}
+	}
+	return res, cc.ctx.Err()
+}
+
+// Find returns the first value for which fn returns true, searching until the context is canceled.
+// Returns ctx.Err() if the search was cut short.
+func (cc *ContextualCollection[K, V]) Find(fn func(value V, key K, collection *Collection[K, V]) bool) (V, bool, error) {
+	keys, values := cc.collection.snapshot()
+	for i, k := range keys {
+		if err := cc.ctx.Err(); err != nil {
+			var zero V
+			return zero, false, err
+		}
+		if fn(values[i], k, cc.collection) {
+			return values[i], true, nil
+		}
+	}
+	var zero V
+	return zero, false, nil
+}
+
+// MapCollectionContext returns a slice of values produced by applying fn to each item until the context of cc is canceled.
+// Returns the partial result and ctx.Err() if iteration was cut short.
+func MapCollectionContext[K comparable, V, R any](cc *ContextualCollection[K, V], fn func(value V, key K, collection *Collection[K, V]) R) ([]R, error) {
+	keys, values := cc.collection.snapshot()
+	res := make([]R, 0, len(keys))
+	for i, k := range keys {
+		if err := cc.ctx.Err(); err != nil {
+			return res, err
+		}
+		res = append(res, fn(values[i], k, cc.collection))
+	}
+	return res, nil
+}
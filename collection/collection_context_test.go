@@ -0,0 +1,155 @@
+package collection_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+func newContextTestCollection() *collection.Collection[int, int] {
+	c := collection.New[int, int]()
+	for i := 0; i < 10; i++ {
+		c.Set(i, i)
+	}
+	return c
+}
+
+// TestContextualCollectionEach tests Each with and without cancellation
+func TestContextualCollectionEach(t *testing.T) {
+	c := newContextTestCollection()
+
+	visited := 0
+	err := collection.WithContext(context.Background(), c).Each(func(value int, key int, coll *collection.Collection[int, int]) {
+		visited++
+	})
+	if err != nil || visited != 10 {
+		t.Errorf("Expected all 10 items without error, got %d (err=%v)", visited, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	visited = 0
+	err = collection.WithContext(ctx, c).Each(func(value int, key int, coll *collection.Collection[int, int]) {
+		visited++
+		if visited == 3 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if visited != 3 {
+		t.Errorf("Iteration should stop after cancellation, visited %d", visited)
+	}
+}
+
+// TestContextualCollectionFilter tests Filter with cancellation
+func TestContextualCollectionFilter(t *testing.T) {
+	c := newContextTestCollection()
+
+	result, err := collection.WithContext(context.Background(), c).Filter(func(value int, key int, coll *collection.Collection[int, int]) bool {
+		return value%2 == 0
+	})
+	if err != nil || result.Size() != 5 {
+		t.Errorf("Expected 5 even items without error, got %d (err=%v)", result.Size(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	seen := 0
+	result, err = collection.WithContext(ctx, c).Filter(func(value int, key int, coll *collection.Collection[int, int]) bool {
+		seen++
+		if seen == 4 {
+			cancel()
+		}
+		return true
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if result.Size() != 4 {
+		t.Errorf("Expected partial result of 4 items, got %d", result.Size())
+	}
+}
+
+// TestContextualCollectionFind tests Find with cancellation
+func TestContextualCollectionFind(t *testing.T) {
+	c := newContextTestCollection()
+
+	value, found, err := collection.WithContext(context.Background(), c).Find(func(value int, key int, coll *collection.Collection[int, int]) bool {
+		return value == 7
+	})
+	if err != nil || !found || value != 7 {
+		t.Errorf("Expected to find 7, got %d (found=%v, err=%v)", value, found, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, found, err = collection.WithContext(ctx, c).Find(func(value int, key int, coll *collection.Collection[int, int]) bool {
+		return true
+	})
+	if found || !errors.Is(err, context.Canceled) {
+		t.Errorf("Find on canceled context should fail with context.Canceled, got found=%v err=%v", found, err)
+	}
+}
+
+// TestContextualCollectionAsync tests EachAsync and FilterAsync with cancellation
+func TestContextualCollectionAsync(t *testing.T) {
+	c := newContextTestCollection()
+	cc := collection.WithContext(context.Background(), c)
+
+	errs, err := cc.EachAsync(4, func(value int, key int) error { return nil })
+	if err != nil || len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v (err=%v)", errs, err)
+	}
+	result, err := cc.FilterAsync(4, func(value int, key int) bool { return value > 4 })
+	if err != nil || result.Size() != 5 {
+		t.Errorf("Expected 5 items, got %d (err=%v)", result.Size(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceled := collection.WithContext(ctx, c)
+
+	called := 0
+	_, err = canceled.EachAsync(1, func(value int, key int) error {
+		called++
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || called != 0 {
+		t.Errorf("EachAsync on canceled context should not run fn, called %d times (err=%v)", called, err)
+	}
+	result, err = canceled.FilterAsync(1, func(value int, key int) bool { return true })
+	if !errors.Is(err, context.Canceled) || result.Size() != 0 {
+		t.Errorf("FilterAsync on canceled context should return empty result, got %d (err=%v)", result.Size(), err)
+	}
+
+	if canceled.Collection() != c {
+		t.Error("Collection should return the wrapped collection")
+	}
+}
+
+// TestMapCollectionContext tests the MapCollectionContext function
+func TestMapCollectionContext(t *testing.T) {
+	c := newContextTestCollection()
+
+	result, err := collection.MapCollectionContext(collection.WithContext(context.Background(), c), func(value int, key int, coll *collection.Collection[int, int]) int {
+		return value * 2
+	})
+	if err != nil || len(result) != 10 {
+		t.Errorf("Expected 10 mapped values, got %d (err=%v)", len(result), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mapped := 0
+	result, err = collection.MapCollectionContext(collection.WithContext(ctx, c), func(value int, key int, coll *collection.Collection[int, int]) int {
+		mapped++
+		if mapped == 2 {
+			cancel()
+		}
+		return value
+	})
+	if !errors.Is(err, context.Canceled) || len(result) != 2 {
+		t.Errorf("Expected partial result of 2 values with context.Canceled, got %d (err=%v)", len(result), err)
+	}
+}
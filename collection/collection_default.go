@@ -0,0 +1,21 @@
+package collection
+
+// defaultCollection is a read-only view whose Get falls back to a default value for missing keys.
+type defaultCollection[K comparable, V any] struct {
+	ReadableCollection[K, V]
+	defaultFn func(key K) V
+}
+
+// WithDefault returns a read-only view of c whose Get never reports a missing key: for absent keys it returns
+// defaultFn(key) without storing it. Has and every other method reflect the actual contents of c.
+func WithDefault[K comparable, V any](c *Collection[K, V], defaultFn func(key K) V) ReadableCollection[K, V] {
+	return &defaultCollection[K, V]{ReadableCollection: c, defaultFn: defaultFn}
+}
+
+// Get retrieves an item from the underlying collection, or the default value if the key is missing.
+func (d *defaultCollection[K, V]) Get(key K) (V, bool) {
+	if val, ok := d.ReadableCollection.Get(key); ok {
+		return val, true
+	}
+	return d.defaultFn(key), true
+}
@@ -0,0 +1,40 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestWithDefault tests default values for missing keys
+func TestWithDefault(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1)
+	calls := 0
+	d := collection.WithDefault(c, func(key string) int {
+		calls++
+		return -1
+	})
+
+	if val, ok := d.Get("a"); !ok || val != 1 {
+		t.Errorf("Expected stored value 1, got %d (ok=%v)", val, ok)
+	}
+	if calls != 0 {
+		t.Error("Default should not be computed for present keys")
+	}
+	if val, ok := d.Get("missing"); !ok || val != -1 {
+		t.Errorf("Expected default -1, got %d (ok=%v)", val, ok)
+	}
+
+	if d.Has("missing") || c.Has("missing") {
+		t.Error("Default values should not be stored")
+	}
+	if d.Size() != 1 {
+		t.Errorf("Expected size 1, got %d", d.Size())
+	}
+
+	// The view reflects later changes to the collection
+	c.Set("b", 2)
+	if val, _ := d.Get("b"); val != 2 || !d.Has("b") {
+		t.Errorf("Expected view to see b=2, got %d", val)
+	}
+}
@@ -0,0 +1,66 @@
+package collection
+
+import "sync"
+
+// derivedCollection is the read-only view returned by Derive.
+type derivedCollection[K comparable, V any] struct {
+	ReadableCollection[K, V]
+}
+
+// Derive returns a read-only view computed from source that is kept up to date as source changes, and a
+// function that stops updating it. For each item of source, transform returns the derived key and value,
+// or keep=false to leave the item out. transform should map distinct source keys to distinct keys.
+//
+// The view is updated synchronously by every method that modifies source, before that method returns, so
+// no change is missed. transform is called while source's write lock is held and must not call methods on
+// source. After stop is called the view keeps its last contents; stop may be called more than once.
+func Derive[K comparable, V, V2 any](source *Collection[K, V], transform func(K, V) (K, V2, bool)) (derived ReadableCollection[K, V2], stop func()) {
+	target := New[K, V2]()
+	srcKeys := make(map[K]K)
+	apply := func(b *writeBatch[K, V2], ch change[K, V]) {
+		switch ch.op {
+		case EventSet:
+			newKey, newValue, keep := transform(ch.key, ch.newValue)
+			if oldKey, ok := srcKeys[ch.key]; ok && (!keep || oldKey != newKey) {
+				b.remove(oldKey)
+				delete(srcKeys, ch.key)
+			}
+			if keep {
+				b.store(newKey, newValue)
+				srcKeys[ch.key] = newKey
+			}
+		case EventDelete:
+			if oldKey, ok := srcKeys[ch.key]; ok {
+				b.remove(oldKey)
+				delete(srcKeys, ch.key)
+			}
+		case EventClear:
+			b.removeAll()
+			clear(srcKeys)
+		}
+	}
+
+	// Take the initial contents and register under the same lock, so that no change is missed or applied twice
+	source.mu.Lock()
+	target.write(func(b *writeBatch[K, V2]) {
+		for k, v := range source.items {
+			apply(b, change[K, V]{op: EventSet, key: k, newValue: v})
+		}
+	})
+	if source.followers == nil {
+		source.followers = make(map[uint64]func(change[K, V]))
+	}
+	source.followerSeq++
+	id := source.followerSeq
+	source.followers[id] = func(ch change[K, V]) {
+		target.write(func(b *writeBatch[K, V2]) { apply(b, ch) })
+	}
+	source.mu.Unlock()
+
+	stop = sync.OnceFunc(func() {
+		source.mu.Lock()
+		defer source.mu.Unlock()
+		delete(source.followers, id)
+	})
+	return &derivedCollection[K, V2]{ReadableCollection: target}, stop
+}
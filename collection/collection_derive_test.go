@@ -0,0 +1,104 @@
+package collection_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestDerive tests the Derive function
+func TestDerive(t *testing.T) {
+	source := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	// Keep even values, upper-cased keys, as strings
+	derived, stop := collection.Derive(source, func(key string, value int) (string, string, bool) {
+		return strings.ToUpper(key), strings.Repeat("x", value), value%2 == 0
+	})
+	defer stop()
+	if derived.Size() != 1 || !derived.Has("B") {
+		t.Fatalf("Expected only B initially, got keys %v", derived.Keys())
+	}
+
+	source.Set("c", 4)
+	if val, _ := derived.Get("C"); val != "xxxx" {
+		t.Errorf("Expected C to be xxxx, got %q", val)
+	}
+
+	// An update that no longer passes the transform removes the derived entry
+	source.Set("b", 3)
+	if derived.Has("B") {
+		t.Error("Expected B to be removed")
+	}
+
+	source.Delete("c")
+	if derived.Has("C") {
+		t.Error("Expected C to be removed")
+	}
+
+	source.Set("d", 2).Clear()
+	if derived.Size() != 0 {
+		t.Errorf("Expected clear to empty the derived collection, got keys %v", derived.Keys())
+	}
+}
+
+// TestDeriveAllMutators tests that changes made by any method are followed without loss
+func TestDeriveAllMutators(t *testing.T) {
+	source := collection.New[int, int]()
+	derived, stop := collection.Derive(source, func(key, value int) (int, int, bool) {
+		return key, value * 10, true
+	})
+	defer stop()
+
+	// Far more changes than a Watch buffer holds
+	for i := 0; i < 1000; i++ {
+		source.Set(i, i)
+	}
+	if derived.Size() != 1000 {
+		t.Fatalf("Expected 1000 derived items, got %d", derived.Size())
+	}
+
+	source.SetDefault(1000, 1)
+	source.Sweep(func(value int, _ int, _ *collection.Collection[int, int]) bool { return value%2 == 1 })
+	source.ApplyToAll(func(value int, key int) int { return value + 1 })
+	if derived.Size() != source.Size() {
+		t.Fatalf("Expected %d derived items, got %d", source.Size(), derived.Size())
+	}
+	for _, key := range source.Keys() {
+		want, _ := source.Get(key)
+		if got, _ := derived.Get(key); got != want*10 {
+			t.Errorf("Key %d: expected %d, got %d", key, want*10, got)
+		}
+	}
+}
+
+// TestDeriveStop tests that a stopped derived collection keeps its last contents
+func TestDeriveStop(t *testing.T) {
+	source := collection.New[string, int]().Set("a", 1)
+	derived, stop := collection.Derive(source, func(key string, value int) (string, int, bool) {
+		return key, value, true
+	})
+	stop()
+	stop() // stopping twice is safe
+
+	source.Set("b", 2)
+	if derived.Size() != 1 || derived.Has("b") {
+		t.Errorf("Expected a stopped derived collection to be unchanged, got keys %v", derived.Keys())
+	}
+}
+
+// TestDeriveReadOnly tests that a derived collection cannot be mutated
+func TestDeriveReadOnly(t *testing.T) {
+	source := collection.New[string, int]().Set("a", 1)
+	derived, stop := collection.Derive(source, func(key string, value int) (string, int, bool) {
+		return key, value * 10, true
+	})
+	defer stop()
+
+	if _, ok := derived.(*collection.Collection[string, int]); ok {
+		t.Error("Derived collection should not be a *Collection")
+	}
+	if clone := derived.Clone(); clone.Set("z", 1) != nil && derived.Has("z") {
+		t.Error("Mutating a clone should not affect the derived collection")
+	}
+}
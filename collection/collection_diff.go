@@ -0,0 +1,77 @@
+package collection
+
+import "reflect"
+
+// DiffResult describes the differences between two collections.
+type DiffResult[K comparable, V any] struct {
+	// Added holds the items whose keys are only in the other collection.
+	Added *Collection[K, V]
+	// Removed holds the items whose keys are only in the original collection.
+	Removed *Collection[K, V]
+	// Changed maps keys present in both collections with different values to their [old, new] value pair.
+	Changed *Collection[K, [2]V]
+}
+
+// Diff compares c with other and reports which keys were added, removed, or changed.
+// Values are compared with reflect.DeepEqual, as in Equals.
+// Diff is a function rather than a method because DiffResult holds a collection of a different value type.
+func Diff[K comparable, V any](c, other *Collection[K, V]) DiffResult[K, V] {
+	diff := DiffResult[K, V]{
+		Added:   New[K, V](),
+		Removed: New[K, V](),
+		Changed: New[K, [2]V](),
+	}
+	if c == other {
+		return diff
+	}
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	for k, v := range c.items {
+		ov, ok := other.items[k]
+		switch {
+		case !ok:
+			diff.Removed.items[k] = v
+		case !reflect.DeepEqual(v, ov):
+			diff.Changed.items[k] = [2]V{v, ov}
+		}
+	}
+	for k, ov := range other.items {
+		if _, ok := c.items[k]; !ok {
+			diff.Added.items[k] = ov
+		}
+	}
+	return diff
+}
+
+// Patch applies diff to c and returns c: keys in diff.Removed are deleted, keys in diff.Added are set,
+// and keys in diff.Changed are set to their new value. Nil parts of diff are skipped.
+// All changes are applied under a single write lock, so readers see either none or all of them.
+// Patch is a function rather than a method for the same reason as Diff.
+func Patch[K comparable, V any](c *Collection[K, V], diff DiffResult[K, V]) *Collection[K, V] {
+	var removed, added []K
+	var addedValues []V
+	if diff.Removed != nil {
+		removed = diff.Removed.Keys()
+	}
+	if diff.Added != nil {
+		added, addedValues = diff.Added.snapshot()
+	}
+	var changed []K
+	var changedValues [][2]V
+	if diff.Changed != nil {
+		changed, changedValues = diff.Changed.snapshot()
+	}
+
+	c.write(func(b *writeBatch[K, V]) {
+		for _, k := range removed {
+			b.delete(k)
+		}
+		for i, k := range added {
+			b.set(k, addedValues[i])
+		}
+		for i, k := range changed {
+			b.set(k, changedValues[i][1])
+		}
+	})
+	return c
+}
@@ -0,0 +1,56 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestDiff tests the Diff function
+func TestDiff(t *testing.T) {
+	before := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+	after := collection.New[string, int]().Set("b", 2).Set("c", 30).Set("d", 4)
+
+	diff := collection.Diff(before, after)
+	if diff.Added.Size() != 1 || !diff.Added.Has("d") {
+		t.Errorf("Expected d to be added, got %v", diff.Added.Keys())
+	}
+	if diff.Removed.Size() != 1 || !diff.Removed.Has("a") {
+		t.Errorf("Expected a to be removed, got %v", diff.Removed.Keys())
+	}
+	if diff.Changed.Size() != 1 {
+		t.Errorf("Expected 1 changed key, got %v", diff.Changed.Keys())
+	}
+	if pair, _ := diff.Changed.Get("c"); pair != [2]int{3, 30} {
+		t.Errorf("Expected c to change from 3 to 30, got %v", pair)
+	}
+
+	same := collection.Diff(before, before.Clone())
+	if same.Added.Size()+same.Removed.Size()+same.Changed.Size() != 0 {
+		t.Error("Diff of equal collections should be empty")
+	}
+	if self := collection.Diff(before, before); self.Changed.Size() != 0 {
+		t.Error("Diff of a collection with itself should be empty")
+	}
+}
+
+// TestPatch tests the Patch function
+func TestPatch(t *testing.T) {
+	before := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+	after := collection.New[string, int]().Set("b", 2).Set("c", 30).Set("d", 4)
+
+	replica := before.Clone()
+	if result := collection.Patch(replica, collection.Diff(before, after)); result != replica {
+		t.Error("Patch should return the collection")
+	}
+	if !replica.Equals(after) {
+		t.Errorf("Patched collection should equal the target, got keys %v", replica.Keys())
+	}
+
+	// Nil parts are skipped
+	partial := collection.DiffResult[string, int]{Added: collection.New[string, int]().Set("e", 5)}
+	collection.Patch(replica, partial)
+	if val, _ := replica.Get("e"); val != 5 || replica.Size() != 4 {
+		t.Errorf("Expected e=5 added to 4 items, got %d items", replica.Size())
+	}
+}
@@ -0,0 +1,219 @@
+package collection
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+)
+
+// ErrUnknownFormat is returned by ExportTo and ImportFrom for a format other than "json", "csv" or "ndjson".
+var ErrUnknownFormat = errors.New("collection: unknown format")
+
+// ndjsonEntry is the form of one line of the "ndjson" format.
+type ndjsonEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// ExportTo streams the items to w in ascending natural key order, in one of these formats:
+//   - "json": the MarshalText layout, a JSON object for string keys and an array of [key, value] pairs otherwise
+//   - "ndjson": one {"key": ..., "value": ...} JSON object per line
+//   - "csv": one key,value record per item, both formatted with fmt.Sprint
+//
+// The read lock is held only while the keys are collected and while each item is read, so writers are not blocked
+// for the whole export. Items deleted during the export are skipped.
+func (c *Collection[K, V]) ExportTo(w io.Writer, format string) error {
+	if format != "json" && format != "ndjson" && format != "csv" {
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	c.mu.RLock()
+	keys := c.keysUnlocked()
+	c.mu.RUnlock()
+	slices.SortFunc(keys, func(a, b K) int {
+		return compareNatural(a, b)
+	})
+
+	bw := bufio.NewWriter(w)
+	var cw *csv.Writer
+	if format == "csv" {
+		cw = csv.NewWriter(bw)
+	}
+	stringKeys := keyIsString[K]()
+	openDelim, closeDelim := "[", "]"
+	if stringKeys {
+		openDelim, closeDelim = "{", "}"
+	}
+	if format == "json" {
+		bw.WriteString(openDelim)
+	}
+	written := 0
+	for _, k := range keys {
+		c.mu.RLock()
+		v, ok := c.items[k]
+		c.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		var err error
+		switch format {
+		case "json":
+			err = writeJSONItem(bw, k, v, stringKeys, written > 0)
+		case "ndjson":
+			err = writeNDJSONItem(bw, k, v)
+		case "csv":
+			err = cw.Write([]string{fmt.Sprint(k), fmt.Sprint(v)})
+		}
+		if err != nil {
+			return err
+		}
+		written++
+	}
+	switch format {
+	case "json":
+		bw.WriteString(closeDelim)
+	case "csv":
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// writeJSONItem writes one item of the "json" format, preceded by a comma unless it is the first.
+func writeJSONItem[K comparable, V any](w *bufio.Writer, key K, value V, stringKeys, comma bool) error {
+	k, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if comma {
+		w.WriteByte(',')
+	}
+	if stringKeys {
+		w.Write(k)
+		w.WriteByte(':')
+		w.Write(v)
+		return nil
+	}
+	w.WriteByte('[')
+	w.Write(k)
+	w.WriteByte(',')
+	w.Write(v)
+	w.WriteByte(']')
+	return nil
+}
+
+// writeNDJSONItem writes one line of the "ndjson" format.
+func writeNDJSONItem[K comparable, V any](w *bufio.Writer, key K, value V) error {
+	line, err := json.Marshal(ndjsonEntry[K, V]{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	w.Write(line)
+	return w.WriteByte('\n')
+}
+
+// ImportFrom reads items from r in a format written by ExportTo and stores each one with Set as it is parsed,
+// so the input is never held in memory as a whole. For "csv", each record's two fields are converted by keyParser
+// and valueParser, which must not be nil; "json" and "ndjson" items are decoded with encoding/json and the parsers may be nil.
+// On error, the items read before it remain stored.
+func (c *Collection[K, V]) ImportFrom(r io.Reader, format string, keyParser func(string) (K, error), valueParser func(string) (V, error)) error {
+	switch format {
+	case "json":
+		return decodeJSONItems(json.NewDecoder(r), func(k K, v V) { c.Set(k, v) })
+	case "ndjson":
+		dec := json.NewDecoder(r)
+		for {
+			var e ndjsonEntry[K, V]
+			if err := dec.Decode(&e); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			c.Set(e.Key, e.Value)
+		}
+	case "csv":
+		if keyParser == nil || valueParser == nil {
+			return errors.New("collection: importing csv requires a keyParser and a valueParser")
+		}
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = 2
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			k, err := keyParser(record[0])
+			if err != nil {
+				return err
+			}
+			v, err := valueParser(record[1])
+			if err != nil {
+				return err
+			}
+			c.Set(k, v)
+		}
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// decodeJSONItems decodes a JSON object or [key, value] pair array from dec, passing each item to fn
+// in document order as soon as it is decoded.
+func decodeJSONItems[K comparable, V any](dec *json.Decoder, fn func(key K, value V)) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		if !keyIsString[K]() {
+			return fmt.Errorf("collection: cannot import a JSON object into a collection with %v keys", reflect.TypeFor[K]())
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			var k K
+			reflect.ValueOf(&k).Elem().SetString(tok.(string))
+			var v V
+			if err := dec.Decode(&v); err != nil {
+				return err
+			}
+			fn(k, v)
+		}
+	case json.Delim('['):
+		for dec.More() {
+			var pair [2]json.RawMessage
+			if err := dec.Decode(&pair); err != nil {
+				return err
+			}
+			var k K
+			var v V
+			if err := json.Unmarshal(pair[0], &k); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(pair[1], &v); err != nil {
+				return err
+			}
+			fn(k, v)
+		}
+	default:
+		return fmt.Errorf("collection: cannot import JSON %v into a collection", tok)
+	}
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,101 @@
+package collection_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionExportTo tests the ExportTo method
+func TestCollectionExportTo(t *testing.T) {
+	c := collection.New[string, int]().Set("b", 2).Set("a", 1).Set("c", 3)
+	for format, want := range map[string]string{
+		"json":   `{"a":1,"b":2,"c":3}`,
+		"ndjson": "{\"key\":\"a\",\"value\":1}\n{\"key\":\"b\",\"value\":2}\n{\"key\":\"c\",\"value\":3}\n",
+		"csv":    "a,1\nb,2\nc,3\n",
+	} {
+		var buf bytes.Buffer
+		if err := c.ExportTo(&buf, format); err != nil {
+			t.Fatalf("ExportTo(%s) returned error: %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("Expected %s export %q, got %q", format, want, buf.String())
+		}
+	}
+
+	pairs := collection.New[int, string]().Set(2, "two").Set(1, "one")
+	var buf bytes.Buffer
+	if err := pairs.ExportTo(&buf, "json"); err != nil {
+		t.Fatalf("ExportTo returned error: %v", err)
+	}
+	if buf.String() != `[[1,"one"],[2,"two"]]` {
+		t.Errorf("Expected [key, value] pairs for non-string keys, got %s", buf.String())
+	}
+
+	var empty bytes.Buffer
+	if err := collection.New[string, int]().ExportTo(&empty, "json"); err != nil || empty.String() != "{}" {
+		t.Errorf("Expected {} for an empty collection, got %q, %v", empty.String(), err)
+	}
+
+	if err := c.ExportTo(&buf, "xml"); !errors.Is(err, collection.ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+// TestCollectionImportFrom tests the ImportFrom method
+func TestCollectionImportFrom(t *testing.T) {
+	source := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+	parseKey := func(s string) (string, error) { return s, nil }
+
+	for _, format := range []string{"json", "ndjson", "csv"} {
+		var buf bytes.Buffer
+		if err := source.ExportTo(&buf, format); err != nil {
+			t.Fatalf("ExportTo(%s) returned error: %v", format, err)
+		}
+		c := collection.New[string, int]().Set("existing", 0)
+		if err := c.ImportFrom(&buf, format, parseKey, strconv.Atoi); err != nil {
+			t.Fatalf("ImportFrom(%s) returned error: %v", format, err)
+		}
+		if c.Size() != 4 || !c.Has("existing") {
+			t.Errorf("Expected %s import to add to the existing items, got keys %v", format, c.Keys())
+		}
+		if val, _ := c.Get("b"); val != 2 {
+			t.Errorf("Expected b = 2 after %s import, got %d", format, val)
+		}
+	}
+
+	pairs := collection.New[int, string]()
+	if err := pairs.ImportFrom(strings.NewReader(`[[1,"one"],[2,"two"]]`), "json", nil, nil); err != nil {
+		t.Fatalf("ImportFrom should accept [key, value] pairs, got %v", err)
+	}
+	if val, _ := pairs.Get(2); val != "two" {
+		t.Errorf("Expected 2 = two, got %q", val)
+	}
+	if err := pairs.ImportFrom(strings.NewReader(`{"1":"one"}`), "json", nil, nil); err == nil {
+		t.Error("ImportFrom should reject a JSON object for non-string keys")
+	}
+
+	c := collection.New[string, int]()
+	err := c.ImportFrom(strings.NewReader("a,1\nb,x\nc,3\n"), "csv", parseKey, strconv.Atoi)
+	if err == nil {
+		t.Error("ImportFrom should return the parser's error")
+	}
+	if !c.Has("a") || c.Has("c") {
+		t.Errorf("Expected items before the error to remain and none after it, got keys %v", c.Keys())
+	}
+
+	if err := c.ImportFrom(strings.NewReader("d,4\n"), "csv", nil, strconv.Atoi); err == nil || c.Has("d") {
+		t.Error("ImportFrom should reject csv without a keyParser before reading")
+	}
+	if err := c.ImportFrom(strings.NewReader("d,4\n"), "csv", parseKey, nil); err == nil || c.Has("d") {
+		t.Error("ImportFrom should reject csv without a valueParser before reading")
+	}
+
+	if err := c.ImportFrom(strings.NewReader(""), "xml", nil, nil); !errors.Is(err, collection.ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package collection
+
+// Fallback is a layered view over a primary collection and ordered fallbacks, such as user preferences
+// over team defaults over global defaults. Reads search the layers in order; writes go to primary only.
+// It is safe for concurrent use as long as the underlying collections are.
+type Fallback[K comparable, V any] struct {
+	primary   *Collection[K, V]
+	fallbacks []*Collection[K, V]
+}
+
+// NewFallback creates a Fallback layered over primary and fallbacks.
+// Methods not provided by Fallback are available on the underlying collections.
+func NewFallback[K comparable, V any](primary *Collection[K, V], fallbacks ...*Collection[K, V]) *Fallback[K, V] {
+	return &Fallback[K, V]{primary: primary, fallbacks: fallbacks}
+}
+
+// Primary returns the collection that receives writes.
+func (f *Fallback[K, V]) Primary() *Collection[K, V] {
+	return f.primary
+}
+
+// Get returns the value for key from the first layer that has it.
+func (f *Fallback[K, V]) Get(key K) (V, bool) {
+	for _, layer := range f.layers() {
+		if val, ok := layer.Get(key); ok {
+			return val, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Has reports whether any layer has key.
+func (f *Fallback[K, V]) Has(key K) bool {
+	for _, layer := range f.layers() {
+		if layer.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys of every layer, each listed once, starting with those of primary.
+func (f *Fallback[K, V]) Keys() []K {
+	seen := make(map[K]struct{})
+	var keys []K
+	for _, layer := range f.layers() {
+		for _, k := range layer.Keys() {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// Size returns the number of distinct keys across all layers.
+func (f *Fallback[K, V]) Size() int {
+	return len(f.Keys())
+}
+
+// Set stores value for key in primary.
+func (f *Fallback[K, V]) Set(key K, value V) *Fallback[K, V] {
+	f.primary.Set(key, value)
+	return f
+}
+
+// Delete removes key from primary. A fallback value for key, if any, becomes visible again.
+func (f *Fallback[K, V]) Delete(key K) bool {
+	return f.primary.Delete(key)
+}
+
+// Clear removes every item from primary, leaving the fallbacks unchanged.
+func (f *Fallback[K, V]) Clear() *Fallback[K, V] {
+	f.primary.Clear()
+	return f
+}
+
+// layers returns primary followed by the fallbacks.
+func (f *Fallback[K, V]) layers() []*Collection[K, V] {
+	return append([]*Collection[K, V]{f.primary}, f.fallbacks...)
+}
@@ -0,0 +1,70 @@
+package collection_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNewFallback tests layered lookups and primary-only mutations
+func TestNewFallback(t *testing.T) {
+	user := collection.New[string, string]().Set("theme", "dark")
+	team := collection.New[string, string]().Set("theme", "light").Set("language", "de")
+	global := collection.New[string, string]().Set("language", "en").Set("timezone", "UTC")
+
+	settings := collection.NewFallback(user, team, global)
+
+	tests := map[string]string{"theme": "dark", "language": "de", "timezone": "UTC"}
+	for key, want := range tests {
+		if val, ok := settings.Get(key); !ok || val != want {
+			t.Errorf("Expected %s=%s, got %q (ok=%v)", key, want, val, ok)
+		}
+	}
+	if _, ok := settings.Get("missing"); ok {
+		t.Error("Get should report keys missing from every layer")
+	}
+	if !settings.Has("timezone") || settings.Has("missing") {
+		t.Error("Has should check every layer")
+	}
+
+	settings.Set("timezone", "CET")
+	if val, _ := user.Get("timezone"); val != "CET" {
+		t.Errorf("Set should write to primary, got %q", val)
+	}
+	if val, _ := global.Get("timezone"); val != "UTC" {
+		t.Errorf("Set should not modify fallbacks, got %q", val)
+	}
+
+	if !settings.Delete("theme") {
+		t.Error("Delete should report removing the key from primary")
+	}
+	if val, _ := settings.Get("theme"); val != "light" {
+		t.Errorf("Expected the team value after deleting the user value, got %q", val)
+	}
+
+	settings.Clear()
+	if user.Size() != 0 || team.Size() != 2 {
+		t.Error("Clear should only empty primary")
+	}
+	if val, _ := settings.Get("language"); val != "de" {
+		t.Errorf("Expected fallbacks to remain after Clear, got %q", val)
+	}
+}
+
+// TestFallbackKeys tests that Keys and Size cover every layer
+func TestFallbackKeys(t *testing.T) {
+	user := collection.New[string, int]().Set("a", 1)
+	global := collection.New[string, int]().Set("a", 2).Set("b", 3)
+	settings := collection.NewFallback(user, global)
+
+	if keys := settings.Keys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected keys [a b], got %v", keys)
+	}
+	if settings.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", settings.Size())
+	}
+	if settings.Primary() != user {
+		t.Error("Primary should return the primary collection")
+	}
+}
@@ -0,0 +1,142 @@
+package collection
+
+import "encoding/json"
+
+// FrozenCollection is an immutable snapshot of a Collection. It needs no locking, so it can be shared
+// freely across goroutines. Its mutating methods panic; use Thaw to obtain a mutable copy.
+type FrozenCollection[K comparable, V any] struct {
+	items map[K]V
+	// keyNormalizer is copied from the frozen collection, so lookups and Thaw treat keys as it did.
+	keyNormalizer func(key K) K
+}
+
+// Freeze returns an immutable snapshot of the collection's current items.
+// Values are copied shallowly, as in Clone.
+func (c *Collection[K, V]) Freeze() *FrozenCollection[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	items := make(map[K]V, len(c.items))
+	for k, v := range c.items {
+		items[k] = v
+	}
+	return &FrozenCollection[K, V]{items: items, keyNormalizer: c.keyNormalizer}
+}
+
+// Thaw creates a new mutable Collection from the frozen items.
+func (f *FrozenCollection[K, V]) Thaw() *Collection[K, V] {
+	c := New[K, V]()
+	c.keyNormalizer = f.keyNormalizer
+	for k, v := range f.items {
+		c.items[k] = v
+	}
+	return c
+}
+
+// Get retrieves an item from the collection.
+func (f *FrozenCollection[K, V]) Get(key K) (V, bool) {
+	val, ok := f.items[f.normalizeKey(key)]
+	return val, ok
+}
+
+// Has checks if a key exists in the collection.
+func (f *FrozenCollection[K, V]) Has(key K) bool {
+	_, ok := f.items[f.normalizeKey(key)]
+	return ok
+}
+
+// HasAll checks if all of the provided keys exist in the collection.
+func (f *FrozenCollection[K, V]) HasAll(keys ...K) bool {
+	for _, k := range keys {
+		if _, ok := f.items[f.normalizeKey(k)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAny checks if any of the provided keys exist in the collection.
+func (f *FrozenCollection[K, V]) HasAny(keys ...K) bool {
+	for _, k := range keys {
+		if _, ok := f.items[f.normalizeKey(k)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of items in the collection.
+func (f *FrozenCollection[K, V]) Size() int {
+	return len(f.items)
+}
+
+// Keys returns all keys in the collection.
+func (f *FrozenCollection[K, V]) Keys() []K {
+	keys := make([]K, 0, len(f.items))
+	for k := range f.items {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values in the collection.
+func (f *FrozenCollection[K, V]) Values() []V {
+	values := make([]V, 0, len(f.items))
+	for _, v := range f.items {
+		values = append(values, v)
+	}
+	return values
+}
+
+// TypedEntries returns all key-value pairs in the collection as typed entries.
+func (f *FrozenCollection[K, V]) TypedEntries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(f.items))
+	for k, v := range f.items {
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+	return entries
+}
+
+// Each calls fn for every item in the collection.
+func (f *FrozenCollection[K, V]) Each(fn func(value V, key K)) *FrozenCollection[K, V] {
+	for k, v := range f.items {
+		fn(v, k)
+	}
+	return f
+}
+
+// ToJSON returns the collection as a JSON array of [key, value] pairs, like Collection.ToJSON.
+func (f *FrozenCollection[K, V]) ToJSON() ([]byte, error) {
+	pairs := make([][2]any, 0, len(f.items))
+	for k, v := range f.items {
+		pairs = append(pairs, [2]any{k, v})
+	}
+	return json.Marshal(pairs)
+}
+
+// Set panics: a FrozenCollection cannot be modified.
+func (f *FrozenCollection[K, V]) Set(key K, value V) *FrozenCollection[K, V] {
+	panic(frozenPanic("Set"))
+}
+
+// Delete panics: a FrozenCollection cannot be modified.
+func (f *FrozenCollection[K, V]) Delete(key K) bool {
+	panic(frozenPanic("Delete"))
+}
+
+// Clear panics: a FrozenCollection cannot be modified.
+func (f *FrozenCollection[K, V]) Clear() *FrozenCollection[K, V] {
+	panic(frozenPanic("Clear"))
+}
+
+// normalizeKey returns key as stored by the collection: unchanged unless the collection normalizes its keys.
+func (f *FrozenCollection[K, V]) normalizeKey(key K) K {
+	if f.keyNormalizer == nil {
+		return key
+	}
+	return f.keyNormalizer(key)
+}
+
+// frozenPanic returns the panic message for calling a mutating method on a FrozenCollection.
+func frozenPanic(method string) string {
+	return "collection: " + method + " called on a FrozenCollection; call Thaw to get a mutable copy"
+}
@@ -0,0 +1,68 @@
+package collection_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionFreeze tests Freeze and the read methods of FrozenCollection
+func TestCollectionFreeze(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	frozen := c.Freeze()
+
+	c.Set("c", 3).Delete("a")
+	if frozen.Size() != 2 || !frozen.Has("a") || frozen.Has("c") {
+		t.Error("Frozen snapshot should not see later changes")
+	}
+	if val, ok := frozen.Get("b"); !ok || val != 2 {
+		t.Errorf("Expected b=2, got %d (ok=%v)", val, ok)
+	}
+	if !frozen.HasAll("a", "b") || frozen.HasAny("c", "d") {
+		t.Error("HasAll/HasAny should reflect the snapshot")
+	}
+	if len(frozen.Keys()) != 2 || len(frozen.Values()) != 2 || len(frozen.TypedEntries()) != 2 {
+		t.Error("Keys, Values and TypedEntries should return every item")
+	}
+	sum := 0
+	frozen.Each(func(value int, key string) { sum += value })
+	if sum != 3 {
+		t.Errorf("Expected Each to visit values summing to 3, got %d", sum)
+	}
+}
+
+// TestFrozenCollectionThaw tests that Thaw returns an independent mutable copy
+func TestFrozenCollectionThaw(t *testing.T) {
+	frozen := collection.New[string, int]().Set("a", 1).Freeze()
+	thawed := frozen.Thaw()
+	thawed.Set("b", 2)
+
+	if thawed.Size() != 2 {
+		t.Errorf("Expected thawed size 2, got %d", thawed.Size())
+	}
+	if frozen.Size() != 1 {
+		t.Error("Modifying the thawed copy should not affect the frozen snapshot")
+	}
+}
+
+// TestFrozenCollectionPanics tests that mutating methods panic
+func TestFrozenCollectionPanics(t *testing.T) {
+	frozen := collection.New[string, int]().Freeze()
+	mutations := map[string]func(){
+		"Set":    func() { frozen.Set("a", 1) },
+		"Delete": func() { frozen.Delete("a") },
+		"Clear":  func() { frozen.Clear() },
+	}
+	for name, mutate := range mutations {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, _ := r.(string); !strings.Contains(msg, name) || !strings.Contains(msg, "Thaw") {
+					t.Errorf("%s should panic with a helpful message, got %v", name, r)
+				}
+			}()
+			mutate()
+		}()
+	}
+}
@@ -0,0 +1,50 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// Hash returns a 64-bit FNV-1a fingerprint of the collection's content.
+// Keys and values are hashed through their JSON encoding, with entries sorted by encoded key,
+// so the result does not depend on iteration order and collections for which Equals is true hash equally.
+// Returns an error if a key or value cannot be JSON-encoded.
+func (c *Collection[K, V]) Hash() (uint64, error) {
+	type encodedEntry struct {
+		key, value []byte
+	}
+	c.mu.RLock()
+	entries := make([]encodedEntry, 0, len(c.items))
+	for k, v := range c.items {
+		key, err := json.Marshal(k)
+		if err != nil {
+			c.mu.RUnlock()
+			return 0, fmt.Errorf("collection: hash key %v: %w", k, err)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			c.mu.RUnlock()
+			return 0, fmt.Errorf("collection: hash value for key %v: %w", k, err)
+		}
+		entries = append(entries, encodedEntry{key: key, value: value})
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(entries, func(a, b encodedEntry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+	h := fnv.New64a()
+	var length [8]byte
+	for _, e := range entries {
+		for _, part := range [][]byte{e.key, e.value} {
+			binary.LittleEndian.PutUint64(length[:], uint64(len(part)))
+			h.Write(length[:])
+			h.Write(part)
+		}
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,43 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionHash tests the Hash method
+func TestCollectionHash(t *testing.T) {
+	c1 := collection.New[string, []int]()
+	c2 := collection.New[string, []int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		c1.Set(key, []int{i})
+	}
+	for i, key := range []string{"e", "d", "c", "b", "a"} {
+		c2.Set(key, []int{4 - i})
+	}
+
+	h1, err := c1.Hash()
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+	h2, _ := c2.Hash()
+	if !c1.Equals(c2) || h1 != h2 {
+		t.Errorf("Equal collections should hash equally, got %d and %d", h1, h2)
+	}
+
+	c2.Set("a", []int{42})
+	if h3, _ := c2.Hash(); h3 == h1 {
+		t.Error("Changing a value should change the hash")
+	}
+
+	empty1, _ := collection.New[string, int]().Hash()
+	empty2, _ := collection.New[string, int]().Hash()
+	if empty1 != empty2 {
+		t.Error("Empty collections should hash equally")
+	}
+
+	if _, err := collection.New[string, func()]().Set("f", func() {}).Hash(); err == nil {
+		t.Error("Hash should fail for values that cannot be encoded")
+	}
+}
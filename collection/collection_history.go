@@ -0,0 +1,74 @@
+package collection
+
+import "time"
+
+// HistoryEntry is a value stored for a key at a point in time, as recorded by the history.
+type HistoryEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	Timestamp time.Time
+}
+
+// EnableHistory starts recording, for each key, every value stored for it, whichever method stores it.
+// At most maxPerKey entries are kept per key, dropping the oldest; a maxPerKey <= 0 keeps every entry.
+// Calling it again changes the limit for future entries.
+func (c *Collection[K, V]) EnableHistory(maxPerKey int) *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.historyEnabled = true
+	c.historyMax = maxPerKey
+	if c.history == nil {
+		c.history = make(map[K][]HistoryEntry[K, V])
+	}
+	return c
+}
+
+// DisableHistory stops recording values and discards all recorded history.
+func (c *Collection[K, V]) DisableHistory() *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.historyEnabled = false
+	c.history = nil
+	return c
+}
+
+// History returns the values recorded for key, oldest first. Deleting a key does not discard its history.
+func (c *Collection[K, V]) History(key K) []HistoryEntry[K, V] {
+	key = c.normalizeKey(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]HistoryEntry[K, V], len(c.history[key]))
+	copy(entries, c.history[key])
+	return entries
+}
+
+// ClearHistory discards the recorded values for key.
+func (c *Collection[K, V]) ClearHistory(key K) *Collection[K, V] {
+	key = c.normalizeKey(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.history, key)
+	return c
+}
+
+// ClearAllHistory discards the recorded values for every key without disabling the history.
+func (c *Collection[K, V]) ClearAllHistory() *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.historyEnabled {
+		c.history = make(map[K][]HistoryEntry[K, V])
+	}
+	return c
+}
+
+// recordHistoryUnlocked appends value to the history of key, dropping the oldest entry if the limit is reached.
+// The caller must hold the write lock and should only call it while the history is enabled.
+func (c *Collection[K, V]) recordHistoryUnlocked(key K, value V) {
+	entry := HistoryEntry[K, V]{Key: key, Value: value, Timestamp: time.Now()}
+	entries := c.history[key]
+	if c.historyMax > 0 && len(entries) >= c.historyMax {
+		n := copy(entries, entries[len(entries)-c.historyMax+1:])
+		entries = entries[:n]
+	}
+	c.history[key] = append(entries, entry)
+}
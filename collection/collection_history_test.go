@@ -0,0 +1,75 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// historyValues returns the values of the recorded history entries.
+func historyValues(entries []collection.HistoryEntry[string, int]) []int {
+	values := make([]int, len(entries))
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// TestCollectionHistory tests recording and limiting per-key history
+func TestCollectionHistory(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 0)
+	if len(c.History("a")) != 0 {
+		t.Error("Nothing should be recorded before EnableHistory")
+	}
+
+	c.EnableHistory(3)
+	for i := 1; i <= 5; i++ {
+		c.Set("a", i)
+	}
+	c.SetOrUpdate("b", 10, func(existing int) int { return existing + 1 })
+	c.Delete("a")
+
+	entries := c.History("a")
+	if got := historyValues(entries); len(got) != 3 || got[0] != 3 || got[1] != 4 || got[2] != 5 {
+		t.Errorf("Expected the last 3 values [3 4 5], got %v", got)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Timestamp.Before(entries[i-1].Timestamp) || entries[i].Key != "a" {
+			t.Error("Entries should be chronological and carry their key")
+		}
+	}
+	if got := historyValues(c.History("b")); len(got) != 1 || got[0] != 10 {
+		t.Errorf("Expected SetOrUpdate to be recorded, got %v", got)
+	}
+
+	c.ClearHistory("a")
+	if len(c.History("a")) != 0 || len(c.History("b")) != 1 {
+		t.Error("ClearHistory should only discard the given key")
+	}
+	c.ClearAllHistory()
+	if len(c.History("b")) != 0 {
+		t.Error("ClearAllHistory should discard every key")
+	}
+	c.Set("b", 11)
+	if len(c.History("b")) != 1 {
+		t.Error("History should keep recording after ClearAllHistory")
+	}
+
+	c.DisableHistory()
+	c.Set("b", 12)
+	if len(c.History("b")) != 0 {
+		t.Error("DisableHistory should discard and stop recording")
+	}
+}
+
+// TestCollectionHistoryUnlimited tests history without a per-key limit
+func TestCollectionHistoryUnlimited(t *testing.T) {
+	c := collection.New[string, int]().EnableHistory(0)
+	for i := 0; i < 100; i++ {
+		c.Set("a", i)
+	}
+	if n := len(c.History("a")); n != 100 {
+		t.Errorf("Expected 100 entries, got %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+package collection
+
+// lifecycleHooks holds the functions registered with OnSet, OnDelete and OnClear, in registration order.
+type lifecycleHooks[K comparable, V any] struct {
+	set    []func(key K, oldValue *V, newValue V)
+	delete []func(key K, value V)
+	clear  []func(previousSize int)
+}
+
+// OnSet registers a hook called for every value stored in the collection, by Set or any other method, with the key,
+// the previous value (nil if the key was absent) and the new value, and returns the collection.
+// Hooks run after the lock is released, in registration order, so they may call methods on the collection.
+func (c *Collection[K, V]) OnSet(hook func(key K, oldValue *V, newValue V)) *Collection[K, V] {
+	c.hookMu.Lock()
+	defer c.hookMu.Unlock()
+	c.hooks.set = append(c.hooks.set, hook)
+	c.listeners.Add(1)
+	return c
+}
+
+// OnDelete registers a hook called for every item removed from the collection, by Delete or any other method other
+// than Clear, with its key and value, and returns the collection.
+// Hooks run after the lock is released, in registration order.
+func (c *Collection[K, V]) OnDelete(hook func(key K, value V)) *Collection[K, V] {
+	c.hookMu.Lock()
+	defer c.hookMu.Unlock()
+	c.hooks.delete = append(c.hooks.delete, hook)
+	c.listeners.Add(1)
+	return c
+}
+
+// OnClear registers a hook called after every Clear with the number of items removed, and returns the collection.
+// Hooks run after the lock is released, in registration order.
+func (c *Collection[K, V]) OnClear(hook func(previousSize int)) *Collection[K, V] {
+	c.hookMu.Lock()
+	defer c.hookMu.Unlock()
+	c.hooks.clear = append(c.hooks.clear, hook)
+	c.listeners.Add(1)
+	return c
+}
+
+// runSetHooks calls the OnSet hooks. It must be called without holding c.mu.
+func (c *Collection[K, V]) runSetHooks(key K, oldValue *V, newValue V) {
+	c.hookMu.RLock()
+	hooks := c.hooks.set
+	c.hookMu.RUnlock()
+	for _, hook := range hooks {
+		hook(key, oldValue, newValue)
+	}
+}
+
+// runDeleteHooks calls the OnDelete hooks. It must be called without holding c.mu.
+func (c *Collection[K, V]) runDeleteHooks(key K, value V) {
+	c.hookMu.RLock()
+	hooks := c.hooks.delete
+	c.hookMu.RUnlock()
+	for _, hook := range hooks {
+		hook(key, value)
+	}
+}
+
+// runClearHooks calls the OnClear hooks. It must be called without holding c.mu.
+func (c *Collection[K, V]) runClearHooks(previousSize int) {
+	c.hookMu.RLock()
+	hooks := c.hooks.clear
+	c.hookMu.RUnlock()
+	for _, hook := range hooks {
+		hook(previousSize)
+	}
+}
@@ -0,0 +1,70 @@
+package collection_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionOnSet tests the OnSet method
+func TestCollectionOnSet(t *testing.T) {
+	c := collection.New[string, int]()
+
+	var calls []string
+	c.OnSet(func(key string, oldValue *int, newValue int) {
+		old := "<nil>"
+		if oldValue != nil {
+			old = fmt.Sprint(*oldValue)
+		}
+		calls = append(calls, fmt.Sprintf("%s: %s -> %d", key, old, newValue))
+	}).OnSet(func(key string, oldValue *int, newValue int) {
+		calls = append(calls, "second")
+	})
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+	c.SetOrUpdate("a", 0, func(existing int) int { return existing + 1 })
+
+	want := []string{"a: <nil> -> 1", "second", "a: 1 -> 2", "second", "a: 2 -> 3", "second"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+}
+
+// TestCollectionOnDelete tests the OnDelete method
+func TestCollectionOnDelete(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1)
+
+	var deleted []collection.Entry[string, int]
+	c.OnDelete(func(key string, value int) {
+		// Hooks run without the lock held
+		if c.Has(key) {
+			t.Errorf("Expected %s to be gone when the hook runs", key)
+		}
+		deleted = append(deleted, collection.Entry[string, int]{Key: key, Value: value})
+	})
+
+	c.Delete("a")
+	c.Delete("missing")
+	if !reflect.DeepEqual(deleted, []collection.Entry[string, int]{{Key: "a", Value: 1}}) {
+		t.Errorf("Expected a single hook call for a, got %v", deleted)
+	}
+}
+
+// TestCollectionOnClear tests the OnClear method
+func TestCollectionOnClear(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	var sizes []int
+	c.OnClear(func(previousSize int) {
+		sizes = append(sizes, previousSize)
+	})
+
+	c.Clear()
+	c.Clear()
+	if !reflect.DeepEqual(sizes, []int{2, 0}) {
+		t.Errorf("Expected previous sizes [2 0], got %v", sizes)
+	}
+}
@@ -0,0 +1,26 @@
+package collection
+
+import "iter"
+
+// NewFromIterator creates a new Collection from the key-value pairs produced by seq.
+// Later pairs for a key replace earlier ones.
+func NewFromIterator[K comparable, V any](seq iter.Seq2[K, V]) *Collection[K, V] {
+	c := New[K, V]()
+	for k, v := range seq {
+		c.items[k] = v
+	}
+	return c
+}
+
+// All returns an iterator over a snapshot of the items, in no particular order, for use with range-over-func.
+// The snapshot is taken when iteration starts, so the loop body may modify the collection.
+func (c *Collection[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := c.snapshot()
+		for i, k := range keys {
+			if !yield(k, values[i]) {
+				return
+			}
+		}
+	}
+}
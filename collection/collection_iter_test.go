@@ -0,0 +1,68 @@
+package collection_test
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNewFromIterator tests the NewFromIterator function
+func TestNewFromIterator(t *testing.T) {
+	seq := func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("b", 2) && yield("a", 3)
+	}
+
+	c := collection.NewFromIterator[string, int](seq)
+	if c.Size() != 2 {
+		t.Errorf("Expected 2 items, got %d", c.Size())
+	}
+	if val, _ := c.Get("a"); val != 3 {
+		t.Errorf("Expected the later pair a = 3, got %d", val)
+	}
+}
+
+// TestCollectionAll tests the All method
+func TestCollectionAll(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+
+	sum := 0
+	for key, value := range c.All() {
+		if want, _ := c.Get(key); value != want {
+			t.Errorf("Expected %s = %d, got %d", key, want, value)
+		}
+		sum += value
+		c.Delete(key) // the loop body may modify the collection
+	}
+	if sum != 6 || c.Size() != 0 {
+		t.Errorf("Expected to visit every item once, got sum %d and %d items left", sum, c.Size())
+	}
+
+	// Breaking out of the loop stops the iterator
+	c.Set("a", 1).Set("b", 2)
+	count := 0
+	for range c.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 iteration, got %d", count)
+	}
+
+	// Round trip through an iterator adapter without intermediate slices
+	doubled := collection.NewFromIterator(double(c.All()))
+	if val, _ := doubled.Get("b"); val != 4 || doubled.Size() != 2 {
+		t.Errorf("Expected b = 4 in 2 items, got %d in %d items", val, doubled.Size())
+	}
+}
+
+// double yields every pair of seq with its value doubled.
+func double[K comparable](seq iter.Seq2[K, int]) iter.Seq2[K, int] {
+	return func(yield func(K, int) bool) {
+		for k, v := range seq {
+			if !yield(k, v*2) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package collection
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// WithLogging returns a wrapper around c that logs Set, Delete and Clear at slog.LevelDebug with their key and value,
+// and Get hits and misses at slog.LevelInfo. Every record carries a "collection" attribute identifying c.
+// Only operations made through the wrapper are logged; c itself is not modified.
+func WithLogging[K comparable, V any](c *Collection[K, V], logger *slog.Logger) *InstrumentedCollection[K, V] {
+	return &InstrumentedCollection[K, V]{Collection: c, ins: &instrumentation{logger: logger, logID: fmt.Sprintf("%p", c)}}
+}
+
+// WithLogging returns a copy of the wrapper that also logs to logger, replacing any previous logger.
+func (ic *InstrumentedCollection[K, V]) WithLogging(logger *slog.Logger) *InstrumentedCollection[K, V] {
+	ins := *ic.ins
+	ins.logger = logger
+	ins.logID = fmt.Sprintf("%p", ic.Collection)
+	return &InstrumentedCollection[K, V]{Collection: ic.Collection, ins: &ins}
+}
@@ -0,0 +1,60 @@
+package collection_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestWithLogging tests that operations are logged with their attributes and levels
+func TestWithLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := collection.WithLogging(collection.New[string, int](), logger)
+
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("missing")
+	c.Delete("a")
+	c.Clear()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 log lines, got %d: %q", len(lines), buf.String())
+	}
+	expected := []string{
+		`level=DEBUG msg="collection set"`,
+		`level=INFO msg="collection get"`,
+		`level=INFO msg="collection get"`,
+		`level=DEBUG msg="collection delete"`,
+		`level=DEBUG msg="collection clear"`,
+	}
+	for i, want := range expected {
+		if !strings.Contains(lines[i], want) || !strings.Contains(lines[i], "collection=0x") {
+			t.Errorf("Line %d: expected %s with a collection id, got %q", i, want, lines[i])
+		}
+	}
+	if !strings.Contains(lines[0], "key=a value=1") {
+		t.Errorf("Expected key and value attributes, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "hit=true") || !strings.Contains(lines[2], "hit=false") {
+		t.Errorf("Expected hit and miss attributes, got %q and %q", lines[1], lines[2])
+	}
+
+	// The wrapped collection is not logged
+	buf.Reset()
+	c.Collection.Set("b", 2)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no logging for the wrapped collection, got %q", buf.String())
+	}
+
+	// Logging and metrics combine on one wrapper
+	m := &recordingMetrics{}
+	c.WithMetrics(m).Set("c", 3)
+	if m.sets != 1 || !strings.Contains(buf.String(), "key=c value=3") {
+		t.Errorf("Expected the set to be both counted and logged, got %d sets and %q", m.sets, buf.String())
+	}
+}
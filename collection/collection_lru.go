@@ -0,0 +1,160 @@
+package collection
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruEntry is the list element payload of an LRUCollection.
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRUCollection is a map-like structure with a fixed capacity that evicts the least recently used entry when full.
+// Get and Set mark an entry as most recently used; Peek does not.
+// It is safe for concurrent use.
+type LRUCollection[K comparable, V any] struct {
+	mu       sync.Mutex
+	items    map[K]*list.Element
+	order    *list.List
+	capacity int
+	onEvict  func(key K, value V)
+}
+
+// NewLRU creates a new LRUCollection holding at most capacity items. A capacity <= 0 means no limit.
+func NewLRU[K comparable, V any](capacity int) *LRUCollection[K, V] {
+	return &LRUCollection[K, V]{
+		items:    make(map[K]*list.Element),
+		order:    list.New(),
+		capacity: capacity,
+	}
+}
+
+// OnEvict registers a function called for each evicted entry, replacing any previous one.
+// It is called after the lock is released.
+func (c *LRUCollection[K, V]) OnEvict(fn func(key K, value V)) *LRUCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = fn
+	return c
+}
+
+// Set adds or updates an item and marks it as most recently used, evicting the least recently used entry if full.
+func (c *LRUCollection[K, V]) Set(key K, value V) *LRUCollection[K, V] {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return c
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	var evicted []*lruEntry[K, V]
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		tail := c.order.Back()
+		entry := tail.Value.(*lruEntry[K, V])
+		c.order.Remove(tail)
+		delete(c.items, entry.key)
+		evicted = append(evicted, entry)
+	}
+	onEvict := c.onEvict
+	c.mu.Unlock()
+
+	if onEvict != nil {
+		for _, entry := range evicted {
+			onEvict(entry.key, entry.value)
+		}
+	}
+	return c
+}
+
+// Get retrieves an item and marks it as most recently used.
+func (c *LRUCollection[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+// Peek retrieves an item without affecting its recency.
+func (c *LRUCollection[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+// Has checks if a key exists without affecting its recency.
+func (c *LRUCollection[K, V]) Has(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.items[key]
+	return ok
+}
+
+// Delete removes an item from the collection.
+func (c *LRUCollection[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.order.Remove(el)
+	delete(c.items, key)
+	return true
+}
+
+// Clear removes all items from the collection.
+func (c *LRUCollection[K, V]) Clear() *LRUCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+	return c
+}
+
+// Size returns the number of items in the collection.
+func (c *LRUCollection[K, V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Capacity returns the maximum number of items the collection can hold.
+func (c *LRUCollection[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Keys returns all keys ordered from most to least recently used.
+func (c *LRUCollection[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+// Values returns all values ordered from most to least recently used.
+func (c *LRUCollection[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]V, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		values = append(values, el.Value.(*lruEntry[K, V]).value)
+	}
+	return values
+}
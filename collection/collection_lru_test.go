@@ -0,0 +1,113 @@
+package collection_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestLRUCollectionEviction tests least-recently-used eviction
+func TestLRUCollectionEviction(t *testing.T) {
+	c := collection.NewLRU[string, int](2)
+
+	var evicted []string
+	c.OnEvict(func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+
+	c.Set("a", 1).Set("b", 2)
+	c.Get("a") // a is now most recently used
+	c.Set("c", 3)
+
+	if c.Has("b") {
+		t.Error("Least recently used entry should have been evicted")
+	}
+	if !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Errorf("Expected eviction of [b], got %v", evicted)
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"c", "a"}) {
+		t.Errorf("Expected keys in MRU order [c a], got %v", keys)
+	}
+	if values := c.Values(); !reflect.DeepEqual(values, []int{3, 1}) {
+		t.Errorf("Expected values in MRU order [3 1], got %v", values)
+	}
+}
+
+// TestLRUCollectionPeek tests that Peek does not affect recency
+func TestLRUCollectionPeek(t *testing.T) {
+	c := collection.NewLRU[string, int](2)
+	c.Set("a", 1).Set("b", 2)
+
+	if val, ok := c.Peek("a"); !ok || val != 1 {
+		t.Errorf("Expected Peek to return 1, got %d (ok=%v)", val, ok)
+	}
+	c.Set("c", 3)
+
+	if c.Has("a") {
+		t.Error("Peek should not have promoted the entry")
+	}
+	if _, ok := c.Peek("missing"); ok {
+		t.Error("Peek on missing key should return false")
+	}
+}
+
+// TestLRUCollectionSetExisting tests updating an existing entry
+func TestLRUCollectionSetExisting(t *testing.T) {
+	c := collection.NewLRU[string, int](2)
+	c.Set("a", 1).Set("b", 2).Set("a", 10)
+
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected keys [a b], got %v", keys)
+	}
+	if val, _ := c.Get("a"); val != 10 {
+		t.Errorf("Expected updated value 10, got %d", val)
+	}
+}
+
+// TestLRUCollectionDelete tests Delete and Clear
+func TestLRUCollectionDelete(t *testing.T) {
+	c := collection.NewLRU[string, int](3)
+	c.Set("a", 1).Set("b", 2)
+
+	if !c.Delete("a") {
+		t.Error("Deleting existing key should return true")
+	}
+	if c.Delete("a") {
+		t.Error("Deleting missing key should return false")
+	}
+
+	c.Clear()
+	if c.Size() != 0 || len(c.Keys()) != 0 {
+		t.Error("Collection should be empty after Clear")
+	}
+	if c.Capacity() != 3 {
+		t.Errorf("Expected capacity 3, got %d", c.Capacity())
+	}
+}
+
+// TestLRUCollectionConcurrentAccess tests concurrent use
+func TestLRUCollectionConcurrentAccess(t *testing.T) {
+	c := collection.NewLRU[int, int](50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Set(n*100+j, j)
+				c.Get(n*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if c.Size() != 50 {
+		t.Errorf("Expected size 50, got %d", c.Size())
+	}
+}
@@ -1,54 +1,94 @@
 package collection
 
-import "reflect"
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
 
 // Map returns a slice of values produced by applying fn to each item.
 func MapCollection[K comparable, V, R any](c *Collection[K, V], fn func(value V, key K, collection *Collection[K, V]) R) []R {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	res := make([]R, 0, len(c.items))
-	for k, v := range c.items {
-		res = append(res, fn(v, k, c))
+	keys, values := c.snapshot()
+	res := make([]R, 0, len(keys))
+	for i, k := range keys {
+		res = append(res, fn(values[i], k, c))
 	}
 	return res
 }
 
 // MapValues returns a new collection with the same keys but values mapped by fn.
 func MapCollectionValues[K comparable, V, R any](c *Collection[K, V], fn func(value V, key K, collection *Collection[K, V]) R) *Collection[K, R] {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	keys, values := c.snapshot()
 	res := New[K, R]()
-	for k, v := range c.items {
-		res.items[k] = fn(v, k, c)
+	for i, k := range keys {
+		res.items[k] = fn(values[i], k, c)
 	}
 	return res
 }
 
 // Reduce applies a function to produce a single value.
 func ReduceCollection[K comparable, V, R any](c *Collection[K, V], fn func(accumulator R, value V, key K, collection *Collection[K, V]) R, initialValue R) R {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	keys, values := c.snapshot()
 	acc := initialValue
-	for k, v := range c.items {
-		acc = fn(acc, v, k, c)
+	for i, k := range keys {
+		acc = fn(acc, values[i], k, c)
 	}
 	return acc
 }
 
 // ReduceRight applies a function to produce a single value, iterating from the end.
 func ReduceRightCollection[K comparable, V, R any](c *Collection[K, V], fn func(accumulator R, value V, key K, collection *Collection[K, V]) R, initialValue R) R {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	keys := c.keysUnlocked()
+	keys, values := c.snapshot()
 	acc := initialValue
 	for i := len(keys) - 1; i >= 0; i-- {
-		k := keys[i]
-		v := c.items[k]
-		acc = fn(acc, v, k, c)
+		acc = fn(acc, values[i], keys[i], c)
 	}
 	return acc
 }
 
+// ScanCollection computes a running reduction, returning a new collection that maps each key to the accumulated
+// result up to and including its item. Since Go maps are unordered, items are visited in ascending natural key order,
+// as in ChunkBy, so the same items always give the same results. fn is called on a snapshot without the lock held.
+func ScanCollection[K comparable, V, R any](c *Collection[K, V], fn func(accumulator R, value V, key K) R, initialValue R) *Collection[K, R] {
+	c.mu.RLock()
+	keys := c.keysUnlocked()
+	slices.SortFunc(keys, func(a, b K) int {
+		return compareNatural(a, b)
+	})
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i] = c.items[k]
+	}
+	c.mu.RUnlock()
+
+	res := New[K, R]()
+	acc := initialValue
+	for i, k := range keys {
+		acc = fn(acc, values[i], k)
+		res.items[k] = acc
+	}
+	return res
+}
+
+// FlatMapKeys expands each item into a collection of new keys, then joins the results into a single collection.
+// When several items produce the same key, the last one processed wins.
+func FlatMapKeys[K comparable, K2 comparable, V any](c *Collection[K, V], fn func(key K, value V, c *Collection[K, V]) *Collection[K2, V]) *Collection[K2, V] {
+	keys, values := c.snapshot()
+	res := New[K2, V]()
+	for i, k := range keys {
+		sub := fn(k, values[i], c)
+		sub.mu.RLock()
+		for subk, subv := range sub.items {
+			res.items[subk] = subv
+		}
+		sub.mu.RUnlock()
+	}
+	return res
+}
+
 // Merge merges two collections together into a new collection.
 func MergeCollection[K comparable, V, O, R any](
 	c *Collection[K, V],
@@ -57,34 +97,41 @@ func MergeCollection[K comparable, V, O, R any](
 	whenInOther func(valueOther O, key K) Keep[R],
 	whenInBoth func(value V, valueOther O, key K) Keep[R],
 ) *Collection[K, R] {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	other.mu.RLock()
-	defer other.mu.RUnlock()
+	// Copy both sides under their read locks, then call the callbacks without any lock held
+	var items map[K]V
+	var otherItems map[K]O
+	func() {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		other.mu.RLock()
+		defer other.mu.RUnlock()
+		items = maps.Clone(c.items)
+		otherItems = maps.Clone(other.items)
+	}()
 	res := New[K, R]()
 	keys := make(map[K]struct{})
-	for k := range c.items {
+	for k := range items {
 		keys[k] = struct{}{}
 	}
-	for k := range other.items {
+	for k := range otherItems {
 		keys[k] = struct{}{}
 	}
 	for k := range keys {
-		_, inSelf := c.items[k]
-		_, inOther := other.items[k]
+		_, inSelf := items[k]
+		_, inOther := otherItems[k]
 		switch {
 		case inSelf && inOther:
-			keep := whenInBoth(c.items[k], other.items[k], k)
+			keep := whenInBoth(items[k], otherItems[k], k)
 			if keep.Keep {
 				res.items[k] = keep.Value
 			}
 		case inSelf:
-			keep := whenInSelf(c.items[k], k)
+			keep := whenInSelf(items[k], k)
 			if keep.Keep {
 				res.items[k] = keep.Value
 			}
 		case inOther:
-			keep := whenInOther(other.items[k], k)
+			keep := whenInOther(otherItems[k], k)
 			if keep.Keep {
 				res.items[k] = keep.Value
 			}
@@ -93,6 +140,134 @@ func MergeCollection[K comparable, V, O, R any](
 	return res
 }
 
+// InnerJoin returns a new collection with the keys present in both collections, each mapped to combine's result.
+// The matching items are copied under both read locks; combine is then called without any lock held.
+func InnerJoin[K comparable, A, B, R any](a *Collection[K, A], b *Collection[K, B], combine func(key K, valueA A, valueB B) R) *Collection[K, R] {
+	type match struct {
+		key K
+		a   A
+		b   B
+	}
+	var matches []match
+	func() {
+		unlock := lockPair(a, false, b, false)
+		defer unlock()
+		for k, va := range a.items {
+			if vb, ok := b.items[k]; ok {
+				matches = append(matches, match{key: k, a: va, b: vb})
+			}
+		}
+	}()
+	res := New[K, R]()
+	for _, m := range matches {
+		res.items[m.key] = combine(m.key, m.a, m.b)
+	}
+	return res
+}
+
+// LeftJoin returns a new collection with every key of a mapped to combine's result.
+// combine receives a nil pointer when b has no item for the key.
+// The items are copied under both read locks; combine is then called without any lock held.
+func LeftJoin[K comparable, A, B, R any](a *Collection[K, A], b *Collection[K, B], combine func(key K, valueA A, valueB *B) R) *Collection[K, R] {
+	type match struct {
+		key K
+		a   A
+		b   *B
+	}
+	var matches []match
+	func() {
+		unlock := lockPair(a, false, b, false)
+		defer unlock()
+		for k, va := range a.items {
+			vb, ok := b.items[k]
+			matches = append(matches, match{key: k, a: va, b: valuePtr(vb, ok)})
+		}
+	}()
+	res := New[K, R]()
+	for _, m := range matches {
+		res.items[m.key] = combine(m.key, m.a, m.b)
+	}
+	return res
+}
+
+// IntersectionOf returns a new collection with the items of c whose keys are also present in other.
+// Unlike the Intersection method, other may hold values of any type.
+func IntersectionOf[K comparable, V, O any](c *Collection[K, V], other *Collection[K, O]) *Collection[K, V] {
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	res := New[K, V]()
+	for k, v := range c.items {
+		if _, ok := other.items[k]; ok {
+			res.items[k] = v
+		}
+	}
+	return res
+}
+
+// DifferenceOf returns a new collection with the items of c whose keys are not present in other.
+// Unlike the Difference method, other may hold values of any type.
+func DifferenceOf[K comparable, V, O any](c *Collection[K, V], other *Collection[K, O]) *Collection[K, V] {
+	unlock := lockPair(c, false, other, false)
+	defer unlock()
+	res := New[K, V]()
+	for k, v := range c.items {
+		if _, ok := other.items[k]; !ok {
+			res.items[k] = v
+		}
+	}
+	return res
+}
+
+// UnionAll returns a new collection with the items of all collections.
+// For keys present in several collections, the value from the earliest one wins. With no arguments it returns an empty collection.
+func UnionAll[K comparable, V any](collections ...*Collection[K, V]) *Collection[K, V] {
+	res := New[K, V]()
+	for _, c := range collections {
+		c.mu.RLock()
+		for k, v := range c.items {
+			if _, ok := res.items[k]; !ok {
+				res.items[k] = v
+			}
+		}
+		c.mu.RUnlock()
+	}
+	return res
+}
+
+// IntersectAll returns a new collection with the keys present in every collection, holding the values from the first.
+// With no arguments it returns an empty collection.
+func IntersectAll[K comparable, V any](collections ...*Collection[K, V]) *Collection[K, V] {
+	if len(collections) == 0 {
+		return New[K, V]()
+	}
+	res := collections[0].Clone()
+	for _, c := range collections[1:] {
+		c.mu.RLock()
+		for k := range res.items {
+			if _, ok := c.items[k]; !ok {
+				delete(res.items, k)
+			}
+		}
+		c.mu.RUnlock()
+	}
+	return res
+}
+
+// UniqueValues returns a new collection where no two items share the same value, keeping the first item encountered.
+func UniqueValues[K comparable, V comparable](c *Collection[K, V]) *Collection[K, V] {
+	return c.UniqueBy(func(value V, key K) any {
+		return value
+	})
+}
+
+// ReplaceAll sets every item of c holding oldValue to newValue under a single write lock,
+// and returns the number of items replaced.
+func ReplaceAll[K comparable, V comparable](c *Collection[K, V], oldValue, newValue V) int {
+	return c.ReplaceAllWhere(func(value V) bool {
+		return value == oldValue
+	}, newValue)
+}
+
 // DefaultSort is the default sort comparison algorithm used in ECMAScript.
 func DefaultSort[K comparable, V any](firstValue, secondValue V, firstKey, secondKey K) int {
 	x := toString(firstValue)
@@ -106,6 +281,78 @@ func DefaultSort[K comparable, V any](firstValue, secondValue V, firstKey, secon
 	return 0
 }
 
+// SortByKey sorts the items of a collection in place by their keys' natural order and returns it.
+func SortByKey[K cmp.Ordered, V any](c *Collection[K, V]) *Collection[K, V] {
+	return c.Sort(func(firstValue, secondValue V, firstKey, secondKey K) int {
+		return cmp.Compare(firstKey, secondKey)
+	})
+}
+
+// SortedKeysNaturally returns the keys of a string-keyed collection in lexicographic order without modifying it.
+func SortedKeysNaturally[V any](c *Collection[string, V]) []string {
+	return c.SortedKeys(strings.Compare)
+}
+
+// PrefixAll returns a new collection with prefix prepended to every key.
+func PrefixAll[V any](c *Collection[string, V], prefix string) *Collection[string, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := New[string, V]()
+	for k, v := range c.items {
+		res.items[prefix+k] = v
+	}
+	return res
+}
+
+// StripPrefix returns a new collection with the items whose keys start with prefix, with prefix removed from their keys.
+// Items whose keys do not start with prefix are excluded.
+func StripPrefix[V any](c *Collection[string, V], prefix string) *Collection[string, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := New[string, V]()
+	for k, v := range c.items {
+		if rest, ok := strings.CutPrefix(k, prefix); ok {
+			res.items[rest] = v
+		}
+	}
+	return res
+}
+
+// PartitionBy splits the items of c into groups keyed by grouper's result, keeping each item's key.
+// Unlike the Partition method, any number of groups can be produced. The returned map is owned by the caller
+// and is not safe for concurrent use, although each collection in it is.
+func PartitionBy[K comparable, V any, G comparable](c *Collection[K, V], grouper func(value V, key K) G) map[G]*Collection[K, V] {
+	keys, values := c.snapshot()
+	res := make(map[G]*Collection[K, V])
+	for i, k := range keys {
+		g := grouper(values[i], k)
+		group, ok := res[g]
+		if !ok {
+			group = New[K, V]()
+			res[g] = group
+		}
+		group.items[k] = values[i]
+	}
+	return res
+}
+
+// GroupInto groups the items of c into sub-collections keyed by grouper's result, keeping each item's key.
+// Unlike GroupBy, which collects values into slices, every group is itself a collection.
+func GroupInto[K comparable, V any, K2 comparable](c *Collection[K, V], grouper func(value V, key K) K2) *Collection[K2, *Collection[K, V]] {
+	keys, values := c.snapshot()
+	res := New[K2, *Collection[K, V]]()
+	for i, k := range keys {
+		g := grouper(values[i], k)
+		group, ok := res.items[g]
+		if !ok {
+			group = New[K, V]()
+			res.items[g] = group
+		}
+		group.items[k] = values[i]
+	}
+	return res
+}
+
 // CombineEntries creates a Collection from a list of entries.
 func CombineEntries[K comparable, V any](
 	entries [][2]any,
@@ -134,6 +381,68 @@ func GroupBy[K comparable, Item any](items []Item, keySelector func(item Item, i
 	return res
 }
 
+// Unfold builds a collection from a seed by calling fn with the current state until it returns false.
+// Each call yields a key, a value, and the next state; for duplicate keys the last value wins.
+func Unfold[K comparable, V, S any](seed S, fn func(state S) (K, V, S, bool)) *Collection[K, V] {
+	res := New[K, V]()
+	state := seed
+	for {
+		k, v, next, ok := fn(state)
+		if !ok {
+			return res
+		}
+		res.items[k] = v
+		state = next
+	}
+}
+
+// FrequenciesOf returns a new collection mapping each distinct value to the number of items holding it.
+func FrequenciesOf[K comparable, V comparable](c *Collection[K, V]) *Collection[V, int] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := New[V, int]()
+	for _, v := range c.items {
+		res.items[v]++
+	}
+	return res
+}
+
+// GroupByValue returns an inverse index mapping each distinct value to the keys holding it.
+// Keys within each group are in ascending natural order (numbers and strings compare naturally, other types by their formatted form).
+func GroupByValue[K comparable, V comparable](c *Collection[K, V]) *Collection[V, []K] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := New[V, []K]()
+	for k, v := range c.items {
+		res.items[v] = append(res.items[v], k)
+	}
+	for _, keys := range res.items {
+		slices.SortFunc(keys, func(a, b K) int {
+			return compareNatural(a, b)
+		})
+	}
+	return res
+}
+
+// compareNatural orders two values of the same type. Numbers and strings compare naturally;
+// anything else compares by its formatted representation.
+func compareNatural(a, b any) int {
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if ra.IsValid() && rb.IsValid() && ra.Kind() == rb.Kind() {
+		switch ra.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(ra.Int(), rb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(ra.Uint(), rb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(ra.Float(), rb.Float())
+		case reflect.String:
+			return strings.Compare(ra.String(), rb.String())
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
 // toString attempts to convert a value to string for sorting.
 func toString(v any) string {
 	return reflect.ValueOf(v).String()
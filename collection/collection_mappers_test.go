@@ -1,8 +1,11 @@
 package collection_test
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/kolosys/atomic/collection"
@@ -252,6 +255,49 @@ func TestReduceRightCollection(t *testing.T) {
 	}, 0)
 }
 
+// TestScanCollection tests the ScanCollection function
+func TestScanCollection(t *testing.T) {
+	c := collection.New[string, int]()
+	sum := func(acc int, value int, key string) int { return acc + value }
+
+	result := collection.ScanCollection(c, sum, 0)
+	if result.Size() != 0 {
+		t.Errorf("ScanCollection on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+	result = collection.ScanCollection(c, sum, 100)
+	if result.Size() != 4 {
+		t.Errorf("Expected 4 running totals, got %d", result.Size())
+	}
+
+	// Items are visited in natural key order
+	for key, want := range map[string]int{"a": 101, "b": 103, "c": 106, "d": 110} {
+		if total, _ := result.Get(key); total != want {
+			t.Errorf("Expected running total %d for %s, got %d", want, key, total)
+		}
+	}
+
+	// Every key maps to a prefix sum: sorting the totals gives strictly increasing values ending at the full sum
+	totals := result.Values()
+	sort.Ints(totals)
+	for i := 1; i < len(totals); i++ {
+		if totals[i] <= totals[i-1] {
+			t.Errorf("Running totals should be strictly increasing, got %v", totals)
+		}
+	}
+	if totals[len(totals)-1] != 110 {
+		t.Errorf("Final running total should be 110, got %d", totals[len(totals)-1])
+	}
+	for _, key := range c.Keys() {
+		value, _ := c.Get(key)
+		total, _ := result.Get(key)
+		if total < 100+value {
+			t.Errorf("Running total for %s should include its own value, got %d", key, total)
+		}
+	}
+}
+
 // TestMergeCollection tests the MergeCollection function
 func TestMergeCollection(t *testing.T) {
 	c1 := collection.New[string, int]()
@@ -364,6 +410,26 @@ func TestMergeCollection(t *testing.T) {
 	}
 }
 
+// TestSortByKey tests the SortByKey function
+func TestSortByKey(t *testing.T) {
+	c := collection.New[string, int]()
+	if result := collection.SortByKey(c); result != c || result.Size() != 0 {
+		t.Error("SortByKey on empty collection should return the same empty collection")
+	}
+
+	c.Set("zebra", 1).Set("alpha", 2).Set("beta", 3)
+	result := collection.SortByKey(c)
+	if result != c {
+		t.Error("SortByKey should return the collection for chaining")
+	}
+	if c.Size() != 3 || !c.HasAll("zebra", "alpha", "beta") {
+		t.Errorf("SortByKey should preserve all entries, got keys %v", c.Keys())
+	}
+	if v, _ := c.Get("alpha"); v != 2 {
+		t.Errorf("SortByKey should preserve values, got %d", v)
+	}
+}
+
 // TestCombineEntries tests the CombineEntries function
 func TestCombineEntries(t *testing.T) {
 	// Test with empty entries
@@ -551,3 +617,441 @@ func TestGroupBy(t *testing.T) {
 		t.Errorf("Index 2 group should contain [300], got %v", group2)
 	}
 }
+
+// TestUniqueValues tests the UniqueValues function
+func TestUniqueValues(t *testing.T) {
+	c := collection.New[string, string]()
+
+	result := collection.UniqueValues(c)
+	if result.Size() != 0 {
+		t.Errorf("UniqueValues on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("a", "x").Set("b", "y").Set("c", "x").Set("d", "z").Set("e", "y")
+	result = collection.UniqueValues(c)
+	if result.Size() != 3 {
+		t.Errorf("Expected 3 unique values, got size %d", result.Size())
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range result.Values() {
+		if seen[v] {
+			t.Errorf("Value %s appears more than once", v)
+		}
+		seen[v] = true
+	}
+	for _, key := range result.Keys() {
+		original, _ := c.Get(key)
+		value, _ := result.Get(key)
+		if original != value {
+			t.Errorf("Retained entry %s should keep its original value", key)
+		}
+	}
+	if c.Size() != 5 {
+		t.Error("Original collection should be unchanged")
+	}
+}
+
+// TestFrequenciesOf tests the FrequenciesOf function
+func TestFrequenciesOf(t *testing.T) {
+	c := collection.New[string, string]()
+
+	result := collection.FrequenciesOf(c)
+	if result.Size() != 0 {
+		t.Errorf("FrequenciesOf on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("alice", "admin").Set("bob", "admin").Set("carol", "admin").Set("dave", "user")
+	result = collection.FrequenciesOf(c)
+	if result.Size() != 2 {
+		t.Errorf("Expected 2 distinct values, got %d", result.Size())
+	}
+	if n, _ := result.Get("admin"); n != 3 {
+		t.Errorf("Expected admin frequency 3, got %d", n)
+	}
+	if n, _ := result.Get("user"); n != 1 {
+		t.Errorf("Expected user frequency 1, got %d", n)
+	}
+}
+
+// TestGroupByValue tests the GroupByValue function
+func TestGroupByValue(t *testing.T) {
+	c := collection.New[string, string]()
+
+	result := collection.GroupByValue(c)
+	if result.Size() != 0 {
+		t.Errorf("GroupByValue on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("carol", "admin").Set("alice", "admin").Set("dave", "user").Set("bob", "admin")
+	result = collection.GroupByValue(c)
+	if result.Size() != 2 {
+		t.Errorf("Expected 2 groups, got %d", result.Size())
+	}
+
+	admins, _ := result.Get("admin")
+	if !reflect.DeepEqual(admins, []string{"alice", "bob", "carol"}) {
+		t.Errorf("Expected sorted admins [alice bob carol], got %v", admins)
+	}
+	users, _ := result.Get("user")
+	if !reflect.DeepEqual(users, []string{"dave"}) {
+		t.Errorf("Expected users [dave], got %v", users)
+	}
+
+	// Integer keys are ordered numerically
+	n := collection.New[int, bool]().Set(10, true).Set(2, true).Set(33, false).Set(1, true)
+	trueKeys, _ := collection.GroupByValue(n).Get(true)
+	if !reflect.DeepEqual(trueKeys, []int{1, 2, 10}) {
+		t.Errorf("Expected numerically sorted keys [1 2 10], got %v", trueKeys)
+	}
+}
+
+// TestFlatMapKeys tests the FlatMapKeys function
+func TestFlatMapKeys(t *testing.T) {
+	c := collection.New[string, string]()
+	aliases := func(key string, value string, coll *collection.Collection[string, string]) *collection.Collection[string, string] {
+		return collection.New[string, string]().Set(key, value).Set(strings.ToLower(key), value)
+	}
+
+	result := collection.FlatMapKeys(c, aliases)
+	if result.Size() != 0 {
+		t.Errorf("FlatMapKeys on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("NY", "New York").Set("LA", "Los Angeles")
+	result = collection.FlatMapKeys(c, aliases)
+	if result.Size() != 4 {
+		t.Errorf("Expected 4 items, got %d", result.Size())
+	}
+	for _, key := range []string{"NY", "ny", "LA", "la"} {
+		if !result.Has(key) {
+			t.Errorf("Expected key %q in result", key)
+		}
+	}
+	if val, _ := result.Get("ny"); val != "New York" {
+		t.Errorf("Expected alias to keep value New York, got %q", val)
+	}
+
+	// Keys can change type
+	lengths := collection.FlatMapKeys(c, func(key string, value string, coll *collection.Collection[string, string]) *collection.Collection[int, string] {
+		return collection.New[int, string]().Set(len(value), value)
+	})
+	if val, ok := lengths.Get(8); !ok || val != "New York" {
+		t.Errorf("Expected key 8 to map to New York, got %q (ok=%v)", val, ok)
+	}
+}
+
+// TestSortedKeysNaturally tests the SortedKeysNaturally function
+func TestSortedKeysNaturally(t *testing.T) {
+	c := collection.New[string, int]().Set("banana", 1).Set("Apple", 2).Set("cherry", 3)
+	keys := collection.SortedKeysNaturally(c)
+	if !reflect.DeepEqual(keys, []string{"Apple", "banana", "cherry"}) {
+		t.Errorf("Expected keys [Apple banana cherry], got %v", keys)
+	}
+}
+
+// TestInnerJoin tests the InnerJoin function
+func TestInnerJoin(t *testing.T) {
+	names := collection.New[int, string]().Set(1, "alice").Set(2, "bob").Set(3, "carol")
+	ages := collection.New[int, int]().Set(1, 30).Set(3, 25).Set(4, 40)
+
+	result := collection.InnerJoin(names, ages, func(id int, name string, age int) string {
+		return fmt.Sprintf("%s:%d", name, age)
+	})
+	if result.Size() != 2 {
+		t.Errorf("Expected 2 joined items, got %d", result.Size())
+	}
+	if val, _ := result.Get(1); val != "alice:30" {
+		t.Errorf("Expected alice:30, got %q", val)
+	}
+	if val, _ := result.Get(3); val != "carol:25" {
+		t.Errorf("Expected carol:25, got %q", val)
+	}
+	if result.Has(2) || result.Has(4) {
+		t.Error("InnerJoin should only keep keys present in both collections")
+	}
+}
+
+// TestLeftJoin tests the LeftJoin function
+func TestLeftJoin(t *testing.T) {
+	names := collection.New[int, string]().Set(1, "alice").Set(2, "bob")
+	ages := collection.New[int, int]().Set(1, 30).Set(4, 40)
+
+	result := collection.LeftJoin(names, ages, func(id int, name string, age *int) string {
+		if age == nil {
+			return name + ":unknown"
+		}
+		return fmt.Sprintf("%s:%d", name, *age)
+	})
+	if result.Size() != 2 {
+		t.Errorf("Expected 2 joined items, got %d", result.Size())
+	}
+	if val, _ := result.Get(1); val != "alice:30" {
+		t.Errorf("Expected alice:30, got %q", val)
+	}
+	if val, _ := result.Get(2); val != "bob:unknown" {
+		t.Errorf("Expected bob:unknown, got %q", val)
+	}
+	if result.Has(4) {
+		t.Error("LeftJoin should not include keys only present in the right collection")
+	}
+}
+
+// TestJoinConcurrent tests that joins in both directions alongside writers do not deadlock
+func TestJoinConcurrent(t *testing.T) {
+	names := collection.New[int, string]().Set(1, "alice")
+	ages := collection.New[int, int]().Set(1, 30)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			collection.InnerJoin(names, ages, func(id int, name string, age int) string { return name })
+		}()
+		go func() {
+			defer wg.Done()
+			collection.LeftJoin(ages, names, func(id int, age int, name *string) int { return age })
+		}()
+		go func(n int) {
+			defer wg.Done()
+			names.Set(2, fmt.Sprint(n))
+		}(i)
+		go func(n int) {
+			defer wg.Done()
+			ages.Set(2, n)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestUnfold tests the Unfold function
+func TestUnfold(t *testing.T) {
+	// The first 10 Fibonacci numbers, keyed by index
+	type fib struct{ i, a, b int }
+	result := collection.Unfold(fib{0, 0, 1}, func(s fib) (int, int, fib, bool) {
+		if s.i >= 10 {
+			return 0, 0, s, false
+		}
+		return s.i, s.a, fib{s.i + 1, s.b, s.a + s.b}, true
+	})
+	if result.Size() != 10 {
+		t.Errorf("Expected 10 items, got %d", result.Size())
+	}
+	if val, _ := result.Get(9); val != 34 {
+		t.Errorf("Expected fib(9)=34, got %d", val)
+	}
+
+	empty := collection.Unfold(0, func(s int) (string, int, int, bool) {
+		return "", 0, s, false
+	})
+	if empty.Size() != 0 {
+		t.Errorf("Expected empty collection, got size %d", empty.Size())
+	}
+}
+
+// TestPrefixAll tests the PrefixAll and StripPrefix functions
+func TestPrefixAll(t *testing.T) {
+	c := collection.New[string, int]().Set("host", 1).Set("port", 2)
+
+	prefixed := collection.PrefixAll(c, "db.")
+	if prefixed.Size() != 2 || !prefixed.HasAll("db.host", "db.port") {
+		t.Errorf("Expected prefixed keys, got %v", prefixed.Keys())
+	}
+
+	config := prefixed.Concat(collection.New[string, int]().Set("cache.ttl", 3))
+	db := collection.StripPrefix(config, "db.")
+	if !db.Equals(c) {
+		t.Errorf("Expected StripPrefix to recover the original keys, got %v", db.Keys())
+	}
+	if db.Has("cache.ttl") || db.Has("ttl") {
+		t.Error("Keys without the prefix should be excluded")
+	}
+}
+
+// TestIntersectionOf tests the IntersectionOf function
+func TestIntersectionOf(t *testing.T) {
+	users := collection.New[string, int]().Set("alice", 30).Set("bob", 25).Set("carol", 40)
+	active := collection.New[string, bool]().Set("alice", true).Set("carol", false).Set("dave", true)
+
+	result := collection.IntersectionOf(users, active)
+	keys := result.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"alice", "carol"}) {
+		t.Errorf("Expected keys [alice carol], got %v", keys)
+	}
+	if val, _ := result.Get("carol"); val != 40 {
+		t.Errorf("Expected values from the first collection, got %d", val)
+	}
+
+	if result := collection.IntersectionOf(users, collection.New[string, bool]()); result.Size() != 0 {
+		t.Errorf("Expected an empty intersection, got size %d", result.Size())
+	}
+}
+
+// TestDifferenceOf tests the DifferenceOf function
+func TestDifferenceOf(t *testing.T) {
+	users := collection.New[string, int]().Set("alice", 30).Set("bob", 25).Set("carol", 40)
+	banned := collection.New[string, struct{}]().Set("bob", struct{}{}).Set("dave", struct{}{})
+
+	result := collection.DifferenceOf(users, banned)
+	keys := result.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"alice", "carol"}) {
+		t.Errorf("Expected keys [alice carol], got %v", keys)
+	}
+
+	if result := collection.DifferenceOf(users, collection.New[string, struct{}]()); result.Size() != 3 {
+		t.Errorf("Expected every item when other is empty, got size %d", result.Size())
+	}
+}
+
+// TestIntersectionOfConcurrent tests that IntersectionOf and DifferenceOf in both directions alongside writers do not deadlock
+func TestIntersectionOfConcurrent(t *testing.T) {
+	x := collection.New[string, int]().Set("a", 1)
+	y := collection.New[string, bool]().Set("a", true)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			collection.IntersectionOf(x, y)
+		}()
+		go func() {
+			defer wg.Done()
+			collection.DifferenceOf(y, x)
+		}()
+		go func(n int) {
+			defer wg.Done()
+			x.Set("b", n)
+		}(i)
+		go func(n int) {
+			defer wg.Done()
+			y.Set("b", n%2 == 0)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestUnionAll tests the UnionAll function
+func TestUnionAll(t *testing.T) {
+	a := collection.New[string, int]().Set("x", 1).Set("y", 2)
+	b := collection.New[string, int]().Set("y", 20).Set("z", 30)
+	c := collection.New[string, int]().Set("z", 300).Set("w", 400)
+
+	result := collection.UnionAll(a, b, c)
+	if result.Size() != 4 {
+		t.Errorf("Expected 4 items, got %d", result.Size())
+	}
+	for key, want := range map[string]int{"x": 1, "y": 2, "z": 30, "w": 400} {
+		if val, _ := result.Get(key); val != want {
+			t.Errorf("Expected %s=%d (earliest collection wins), got %d", key, want, val)
+		}
+	}
+
+	if result := collection.UnionAll[string, int](); result.Size() != 0 {
+		t.Errorf("UnionAll with no collections should be empty, got size %d", result.Size())
+	}
+}
+
+// TestIntersectAll tests the IntersectAll function
+func TestIntersectAll(t *testing.T) {
+	a := collection.New[string, int]().Set("x", 1).Set("y", 2).Set("z", 3)
+	b := collection.New[string, int]().Set("y", 20).Set("z", 30)
+	c := collection.New[string, int]().Set("z", 300).Set("y", 200).Set("w", 400)
+
+	result := collection.IntersectAll(a, b, c)
+	keys := result.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"y", "z"}) {
+		t.Errorf("Expected keys [y z], got %v", keys)
+	}
+	if val, _ := result.Get("z"); val != 3 {
+		t.Errorf("Expected values from the first collection, got %d", val)
+	}
+
+	if result := collection.IntersectAll(a); result.Size() != 3 {
+		t.Errorf("IntersectAll of one collection should copy it, got size %d", result.Size())
+	}
+	if result := collection.IntersectAll[string, int](); result.Size() != 0 {
+		t.Errorf("IntersectAll with no collections should be empty, got size %d", result.Size())
+	}
+}
+
+// TestPartitionBy tests the PartitionBy function
+func TestPartitionBy(t *testing.T) {
+	scores := collection.New[string, int]().Set("alice", 95).Set("bob", 72).Set("carol", 88).Set("dave", 55).Set("eve", 91)
+
+	groups := collection.PartitionBy(scores, func(score int, name string) string {
+		switch {
+		case score >= 90:
+			return "A"
+		case score >= 70:
+			return "B"
+		default:
+			return "F"
+		}
+	})
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	for grade, want := range map[string][]string{"A": {"alice", "eve"}, "B": {"bob", "carol"}, "F": {"dave"}} {
+		keys := groups[grade].Keys()
+		sort.Strings(keys)
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("Expected group %s to hold %v, got %v", grade, want, keys)
+		}
+	}
+	if val, _ := groups["B"].Get("carol"); val != 88 {
+		t.Errorf("Expected groups to keep values, got %d", val)
+	}
+
+	if empty := collection.PartitionBy(collection.New[string, int](), func(int, string) bool { return true }); len(empty) != 0 {
+		t.Errorf("Expected no groups for an empty collection, got %d", len(empty))
+	}
+}
+
+// TestGroupInto tests the GroupInto function
+func TestGroupInto(t *testing.T) {
+	ages := collection.New[string, int]().Set("alice", 34).Set("bob", 27).Set("carol", 31).Set("dave", 22)
+
+	byDecade := collection.GroupInto(ages, func(age int, name string) int {
+		return age / 10 * 10
+	})
+	if byDecade.Size() != 2 {
+		t.Fatalf("Expected 2 groups, got %d", byDecade.Size())
+	}
+	thirties, _ := byDecade.Get(30)
+	keys := thirties.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"alice", "carol"}) {
+		t.Errorf("Expected group 30 to hold [alice carol], got %v", keys)
+	}
+	if age, _ := thirties.Get("carol"); age != 31 {
+		t.Errorf("Expected groups to keep values, got %d", age)
+	}
+	twenties, _ := byDecade.Get(20)
+	if twenties.Size() != 2 {
+		t.Errorf("Expected group 20 to hold 2 items, got %d", twenties.Size())
+	}
+
+	if empty := collection.GroupInto(collection.New[string, int](), func(int, string) int { return 0 }); empty.Size() != 0 {
+		t.Errorf("Expected no groups for an empty collection, got %d", empty.Size())
+	}
+}
+
+// TestReplaceAll tests the ReplaceAll function
+func TestReplaceAll(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 1)
+
+	if n := collection.ReplaceAll(c, 1, 10); n != 2 {
+		t.Errorf("Expected 2 replacements, got %d", n)
+	}
+	for key, want := range map[string]int{"a": 10, "b": 2, "c": 10} {
+		if val, _ := c.Get(key); val != want {
+			t.Errorf("Expected %s = %d, got %d", key, want, val)
+		}
+	}
+	if n := collection.ReplaceAll(c, 99, 0); n != 0 {
+		t.Errorf("Expected no replacements for a missing value, got %d", n)
+	}
+}
@@ -0,0 +1,139 @@
+package collection
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Memoize creates a new Collection that loads missing values on Get by calling loader and caches the results.
+// Concurrent Gets for the same missing key share a single loader call. Values whose loader returns an error are
+// not cached, and Get reports them as missing. Other methods, including Has, only see values already cached.
+func Memoize[K comparable, V any](loader func(key K) (V, error)) *Collection[K, V] {
+	c := New[K, V]()
+	c.loader = loader
+	return c
+}
+
+// Bust removes the cached value for key so that the next Get loads it again. Returns true if a value was removed.
+func (c *Collection[K, V]) Bust(key K) bool {
+	c.flights.forget(key)
+	c.computeFlights.forget(key)
+	return c.Delete(key)
+}
+
+// GetOrCompute returns the value for key if it exists, otherwise stores and returns the result of fn.
+// Concurrent calls for the same missing key share a single call of fn. If the key is set by other means while fn runs,
+// that value is kept and returned instead. If fn panics, every call sharing it panics.
+func (c *Collection[K, V]) GetOrCompute(key K, fn func() V) V {
+	key = c.normalizeKey(key)
+	c.mu.RLock()
+	val, ok := c.items[key]
+	c.mu.RUnlock()
+	if ok {
+		return val
+	}
+	val, err := c.computeFlights.do(key, func() (V, error) {
+		c.mu.RLock()
+		val, ok := c.items[key]
+		c.mu.RUnlock()
+		if ok {
+			return val, nil
+		}
+		return c.SetDefault(key, fn()), nil
+	})
+	if err != nil {
+		// The shared call of fn panicked in another goroutine
+		panic(err)
+	}
+	return val
+}
+
+// load fetches the value for key through the loader, sharing the call with concurrent loads of the same key.
+func (c *Collection[K, V]) load(key K) (V, bool) {
+	val, err := c.flights.do(key, func() (V, error) {
+		c.mu.RLock()
+		val, ok := c.items[key]
+		c.mu.RUnlock()
+		if ok {
+			return val, nil
+		}
+		val, err := c.loader(key)
+		if err == nil {
+			c.Set(key, val)
+		}
+		return val, err
+	})
+	if err != nil {
+		var zero V
+		return zero, false
+	}
+	return val, true
+}
+
+// flightCall is an in-progress or completed call of a flightGroup.
+type flightCall[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// flightGroup deduplicates concurrent calls for the same key. The zero value is ready to use.
+type flightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+// flightPanic is the error returned to the callers sharing a call whose function panicked.
+type flightPanic struct {
+	value any
+}
+
+func (p *flightPanic) Error() string {
+	return fmt.Sprintf("collection: shared call panicked: %v", p.value)
+}
+
+// do calls fn once for concurrent callers with the same key and returns its result to all of them.
+// If fn panics, the panic continues in the caller that ran fn and the others receive a *flightPanic error.
+func (g *flightGroup[K, V]) do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	call := &flightCall[V]{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	completed := false
+	defer func() {
+		var r any
+		if !completed {
+			r = recover()
+			call.err = &flightPanic{value: r}
+		}
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		call.wg.Done()
+		if r != nil {
+			panic(r)
+		}
+	}()
+	call.val, call.err = fn()
+	completed = true
+	return call.val, call.err
+}
+
+// forget makes the next call for key start anew instead of joining an in-progress one.
+func (g *flightGroup[K, V]) forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
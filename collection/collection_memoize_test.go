@@ -0,0 +1,191 @@
+package collection_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestMemoize tests that values are loaded once and cached
+func TestMemoize(t *testing.T) {
+	calls := 0
+	c := collection.Memoize(func(key string) (int, error) {
+		calls++
+		return len(key), nil
+	})
+
+	if c.Has("hello") {
+		t.Error("Has should not trigger a load")
+	}
+	if val, ok := c.Get("hello"); !ok || val != 5 {
+		t.Errorf("Expected 5, got %d (ok=%v)", val, ok)
+	}
+	if val, ok := c.Get("hello"); !ok || val != 5 {
+		t.Errorf("Expected cached 5, got %d (ok=%v)", val, ok)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 loader call, got %d", calls)
+	}
+	if !c.Has("hello") || c.Size() != 1 {
+		t.Error("Loaded value should be cached in the collection")
+	}
+
+	if !c.Bust("hello") {
+		t.Error("Bust should report removing a cached value")
+	}
+	c.Get("hello")
+	if calls != 2 {
+		t.Errorf("Expected reload after Bust, got %d loader calls", calls)
+	}
+}
+
+// TestMemoizeError tests that failed loads are not cached
+func TestMemoizeError(t *testing.T) {
+	calls := 0
+	c := collection.Memoize(func(key string) (int, error) {
+		calls++
+		return 0, errors.New("unavailable")
+	})
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get should report a failed load as missing")
+	}
+	c.Get("a")
+	if calls != 2 || c.Size() != 0 {
+		t.Errorf("Failed loads should not be cached, got %d calls and size %d", calls, c.Size())
+	}
+}
+
+// TestMemoizeSingleFlight tests that concurrent Gets share one loader call
+func TestMemoizeSingleFlight(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := collection.Memoize(func(key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, ok := c.Get("key"); !ok || val != "value" {
+				t.Errorf("Expected value, got %q (ok=%v)", val, ok)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 loader call, got %d", n)
+	}
+}
+
+// TestCollectionGetOrCompute tests the GetOrCompute method
+func TestCollectionGetOrCompute(t *testing.T) {
+	c := collection.New[string, int]().Set("present", 1)
+
+	if val := c.GetOrCompute("present", func() int {
+		t.Error("fn should not be called for a present key")
+		return 0
+	}); val != 1 {
+		t.Errorf("Expected the existing value 1, got %d", val)
+	}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make([]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			results[n] = c.GetOrCompute("missing", func() int {
+				calls.Add(1)
+				<-release
+				return 42
+			})
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected fn to be called once, got %d", calls.Load())
+	}
+	for _, r := range results {
+		if r != 42 {
+			t.Fatalf("Expected every caller to get 42, got %v", results)
+		}
+	}
+	if val, ok := c.Get("missing"); !ok || val != 42 {
+		t.Errorf("Expected the computed value to be stored, got %d, %v", val, ok)
+	}
+}
+
+// TestCollectionGetOrComputeSeparateFlights tests that GetOrCompute does not share a call with a Memoize loader
+func TestCollectionGetOrComputeSeparateFlights(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	c := collection.Memoize(func(key string) (int, error) {
+		close(started)
+		<-release
+		return 0, errors.New("load failed")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Get("k")
+	}()
+	<-started
+	if val := c.GetOrCompute("k", func() int { return 7 }); val != 7 {
+		t.Errorf("Expected GetOrCompute to compute 7 while a load is in flight, got %d", val)
+	}
+	close(release)
+	<-done
+}
+
+// TestCollectionGetOrComputePanic tests that a panic in fn reaches every caller sharing it
+func TestCollectionGetOrComputePanic(t *testing.T) {
+	c := collection.New[string, int]()
+	started, release := make(chan struct{}), make(chan struct{})
+
+	var wg sync.WaitGroup
+	panics := make([]any, 2)
+	for i := range panics {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			defer func() { panics[n] = recover() }()
+			c.GetOrCompute("k", func() int {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}(i)
+		if i == 0 {
+			<-started
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, p := range panics {
+		if p == nil {
+			t.Errorf("Caller %d should have panicked", i)
+		}
+	}
+	if c.Has("k") {
+		t.Error("Nothing should be stored when fn panics")
+	}
+}
@@ -0,0 +1,127 @@
+package collection
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// MetricsCollector receives operational metrics from a collection instrumented with WithMetrics.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	IncGet()
+	IncSet()
+	IncDelete()
+	// RecordSize is called with the number of items after each Set, Delete and Clear.
+	RecordSize(size int)
+	// RecordLatency is called after each operation with its name ("get", "set", "delete" or "clear") and duration.
+	RecordLatency(op string, d time.Duration)
+}
+
+// InstrumentedCollection wraps a Collection so that Get, Set, Delete and Clear called through the wrapper are
+// reported to its observers. The wrapped collection itself is not modified: operations made on it directly,
+// or through the other methods promoted from it, are not reported.
+type InstrumentedCollection[K comparable, V any] struct {
+	*Collection[K, V]
+	ins *instrumentation
+}
+
+// WithMetrics returns a wrapper around c whose Get, Set, Delete and Clear report to m.
+func WithMetrics[K comparable, V any](c *Collection[K, V], m MetricsCollector) *InstrumentedCollection[K, V] {
+	return &InstrumentedCollection[K, V]{Collection: c, ins: &instrumentation{metrics: m}}
+}
+
+// WithMetrics returns a copy of the wrapper that also reports to m, replacing any previous collector.
+func (ic *InstrumentedCollection[K, V]) WithMetrics(m MetricsCollector) *InstrumentedCollection[K, V] {
+	ins := *ic.ins
+	ins.metrics = m
+	return &InstrumentedCollection[K, V]{Collection: ic.Collection, ins: &ins}
+}
+
+// Get retrieves an item from the wrapped collection and reports the operation.
+func (ic *InstrumentedCollection[K, V]) Get(key K) (V, bool) {
+	start := time.Now()
+	val, ok := ic.Collection.Get(key)
+	ic.ins.observe(opGet, start, 0, key, val, ok)
+	return val, ok
+}
+
+// Set adds or updates an item in the wrapped collection, reports the operation, and returns the wrapper.
+func (ic *InstrumentedCollection[K, V]) Set(key K, value V) *InstrumentedCollection[K, V] {
+	start := time.Now()
+	existed, size := ic.Collection.set(key, value)
+	ic.ins.observe(opSet, start, size, key, value, existed)
+	return ic
+}
+
+// Delete removes an item from the wrapped collection and reports the operation.
+func (ic *InstrumentedCollection[K, V]) Delete(key K) bool {
+	start := time.Now()
+	old, removed, size := ic.Collection.delete(key)
+	ic.ins.observe(opDelete, start, size, key, old, removed)
+	return removed
+}
+
+// Clear removes all items from the wrapped collection, reports the operation, and returns the wrapper.
+func (ic *InstrumentedCollection[K, V]) Clear() *InstrumentedCollection[K, V] {
+	start := time.Now()
+	size := ic.Collection.clear()
+	ic.ins.observe(opClear, start, size, nil, nil, false)
+	return ic
+}
+
+// Operation names reported by instrumented collections.
+const (
+	opGet    = "get"
+	opSet    = "set"
+	opDelete = "delete"
+	opClear  = "clear"
+)
+
+// instrumentation holds the observers of an InstrumentedCollection. It is copied, never modified.
+type instrumentation struct {
+	metrics MetricsCollector
+	logger  *slog.Logger
+	logID   string
+}
+
+// observe reports a completed operation on key. size is the number of items after the operation,
+// and found reports whether key was present beforehand.
+func (ins *instrumentation) observe(op string, start time.Time, size int, key, value any, found bool) {
+	if ins.logger != nil {
+		ins.log(op, key, value, found)
+	}
+	m := ins.metrics
+	if m == nil {
+		return
+	}
+	switch op {
+	case opGet:
+		m.IncGet()
+	case opSet:
+		m.IncSet()
+		m.RecordSize(size)
+	case opDelete:
+		m.IncDelete()
+		m.RecordSize(size)
+	case opClear:
+		m.RecordSize(size)
+	}
+	m.RecordLatency(op, time.Since(start))
+}
+
+// log writes a structured record for an operation to the attached logger.
+func (ins *instrumentation) log(op string, key, value any, found bool) {
+	attrs := []slog.Attr{slog.String("collection", ins.logID)}
+	level := slog.LevelDebug
+	switch op {
+	case opGet:
+		level = slog.LevelInfo
+		attrs = append(attrs, slog.Any("key", key), slog.Bool("hit", found))
+	case opSet:
+		attrs = append(attrs, slog.Any("key", key), slog.Any("value", value))
+	case opDelete:
+		attrs = append(attrs, slog.Any("key", key), slog.Bool("existed", found))
+	}
+	ins.logger.LogAttrs(context.Background(), level, "collection "+op, attrs...)
+}
@@ -0,0 +1,76 @@
+package collection_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// recordingMetrics is a MetricsCollector that records what it receives.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	gets      int
+	sets      int
+	deletes   int
+	sizes     []int
+	latencies map[string]int
+}
+
+func (m *recordingMetrics) IncGet()    { m.mu.Lock(); m.gets++; m.mu.Unlock() }
+func (m *recordingMetrics) IncSet()    { m.mu.Lock(); m.sets++; m.mu.Unlock() }
+func (m *recordingMetrics) IncDelete() { m.mu.Lock(); m.deletes++; m.mu.Unlock() }
+
+func (m *recordingMetrics) RecordSize(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sizes = append(m.sizes, size)
+}
+
+func (m *recordingMetrics) RecordLatency(op string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.latencies == nil {
+		m.latencies = make(map[string]int)
+	}
+	m.latencies[op]++
+}
+
+// TestWithMetrics tests that operations are reported to the collector
+func TestWithMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	c := collection.WithMetrics(collection.New[string, int](), m)
+
+	c.Set("a", 1).Set("b", 2)
+	c.Get("a")
+	c.Get("missing")
+	c.Delete("a")
+	c.Clear()
+
+	if m.gets != 2 || m.sets != 2 || m.deletes != 1 {
+		t.Errorf("Expected 2 gets, 2 sets and 1 delete, got %d, %d and %d", m.gets, m.sets, m.deletes)
+	}
+	if len(m.sizes) != 4 || m.sizes[0] != 1 || m.sizes[1] != 2 || m.sizes[2] != 1 || m.sizes[3] != 0 {
+		t.Errorf("Expected sizes [1 2 1 0], got %v", m.sizes)
+	}
+	expected := map[string]int{"get": 2, "set": 2, "delete": 1, "clear": 1}
+	for op, n := range expected {
+		if m.latencies[op] != n {
+			t.Errorf("Expected %d %s latencies, got %d", n, op, m.latencies[op])
+		}
+	}
+
+	// The wrapped collection is left uninstrumented
+	c.Collection.Set("c", 3)
+	if m.sets != 2 {
+		t.Errorf("Expected no reporting for the wrapped collection, got %d sets", m.sets)
+	}
+
+	// A derived wrapper reports to its own collector only
+	other := &recordingMetrics{}
+	c.WithMetrics(other).Set("d", 4)
+	if other.sets != 1 || m.sets != 2 {
+		t.Errorf("Expected the derived wrapper to report 1 set to its collector only, got %d and %d", other.sets, m.sets)
+	}
+}
@@ -0,0 +1,37 @@
+package collection
+
+// CollectionOp identifies the operation passed to a CollectionMiddleware: OpSet, OpDelete, or OpClear.
+type CollectionOp = EventType
+
+// Operations seen by middleware.
+const (
+	OpSet    CollectionOp = EventSet
+	OpDelete CollectionOp = EventDelete
+	OpClear  CollectionOp = EventClear
+)
+
+// CollectionMiddleware wraps every change to a collection: each value stored is an OpSet, each item removed an OpDelete,
+// and each Clear an OpClear, whichever method makes the change. It receives the operation, the key (zero for OpClear),
+// a pointer to the value being stored (nil for OpDelete and OpClear), and next, which performs the rest of the chain
+// and then the operation itself. A middleware may change *value before calling next; not calling next blocks the operation.
+type CollectionMiddleware[K comparable, V any] func(op CollectionOp, key K, value *V, next func())
+
+// Use prepends m to the middleware chain and returns the collection. The most recently added middleware runs first.
+// Middleware runs with the write lock held, so it must not call methods on the collection.
+// Revert restores recorded values without running middleware.
+func (c *Collection[K, V]) Use(m CollectionMiddleware[K, V]) *Collection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	chain := make([]CollectionMiddleware[K, V], 0, len(c.middleware)+1)
+	c.middleware = append(append(chain, m), c.middleware...)
+	return c
+}
+
+// runMiddleware calls chain in order, ending with op.
+func runMiddleware[K comparable, V any](chain []CollectionMiddleware[K, V], kind CollectionOp, key K, value *V, op func()) {
+	if len(chain) == 0 {
+		op()
+		return
+	}
+	chain[0](kind, key, value, func() { runMiddleware(chain[1:], kind, key, value, op) })
+}
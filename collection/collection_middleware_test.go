@@ -0,0 +1,70 @@
+package collection_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionUse tests the Use method
+func TestCollectionUse(t *testing.T) {
+	c := collection.New[string, string]()
+
+	var order []string
+	c.Use(func(op collection.CollectionOp, key string, value *string, next func()) {
+		order = append(order, "inner "+op.String())
+		if op == collection.OpSet {
+			*value = strings.ToUpper(*value)
+		}
+		next()
+	}).Use(func(op collection.CollectionOp, key string, value *string, next func()) {
+		order = append(order, "outer "+op.String())
+		next()
+	})
+
+	c.Set("a", "hello")
+	if val, _ := c.Get("a"); val != "HELLO" {
+		t.Errorf("Expected middleware to transform the value to HELLO, got %q", val)
+	}
+	if !c.Delete("a") {
+		t.Error("Expected Delete to report the removed item")
+	}
+	c.Clear()
+
+	want := []string{"outer set", "inner set", "outer delete", "inner delete", "outer clear", "inner clear"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected %v, got %v", want, order)
+	}
+}
+
+// TestCollectionUseBlocks tests that middleware can block an operation by not calling next
+func TestCollectionUseBlocks(t *testing.T) {
+	c := collection.New[string, int]().Set("keep", 1)
+
+	c.Use(func(op collection.CollectionOp, key string, value *int, next func()) {
+		if op == collection.OpSet && *value < 0 {
+			return
+		}
+		if op == collection.OpDelete && key == "keep" {
+			return
+		}
+		if op == collection.OpClear {
+			return
+		}
+		next()
+	})
+
+	c.Set("neg", -1)
+	if c.Has("neg") {
+		t.Error("Expected negative values to be rejected")
+	}
+	if c.Delete("keep") {
+		t.Error("Expected a blocked Delete to return false")
+	}
+	c.Clear()
+	if !c.Has("keep") {
+		t.Error("Expected blocked Delete and Clear to leave keep in place")
+	}
+}
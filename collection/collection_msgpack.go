@@ -0,0 +1,61 @@
+package collection
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// ToMsgpack returns the collection as a MessagePack map. Keys are written in ascending natural order
+// (numbers and strings compare naturally, other types by their formatted form), so equal collections encode identically.
+func (c *Collection[K, V]) ToMsgpack() ([]byte, error) {
+	return msgpack.Marshal(c)
+}
+
+// NewFromMsgpack creates a new Collection from MessagePack produced by ToMsgpack.
+func NewFromMsgpack[K comparable, V any](data []byte) (*Collection[K, V], error) {
+	c := New[K, V]()
+	if err := msgpack.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder, using the same layout as ToMsgpack.
+func (c *Collection[K, V]) EncodeMsgpack(enc *msgpack.Encoder) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := enc.EncodeMapLen(len(c.items)); err != nil {
+		return err
+	}
+	for _, k := range c.sortedKeysUnlocked(func(_, _ V, a, b K) int { return compareNatural(a, b) }) {
+		if err := enc.Encode(k); err != nil {
+			return err
+		}
+		if err := enc.Encode(c.items[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder, replacing the contents of the collection. A nil map decodes as empty.
+func (c *Collection[K, V]) DecodeMsgpack(dec *msgpack.Decoder) error {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return err
+	}
+	entries := make([]Entry[K, V], 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		var k K
+		var v V
+		if err := dec.Decode(&k); err != nil {
+			return err
+		}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+
+	c.write(func(b *writeBatch[K, V]) {
+		b.replace(entries)
+	})
+	return nil
+}
@@ -0,0 +1,85 @@
+package collection_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionMsgpack tests MessagePack round-tripping
+func TestCollectionMsgpack(t *testing.T) {
+	c := collection.New[string, int]().Set("b", 2).Set("a", 1).Set("c", 3)
+
+	data, err := c.ToMsgpack()
+	if err != nil {
+		t.Fatalf("ToMsgpack returned error: %v", err)
+	}
+	json, _ := c.ToJSON()
+	if len(data) >= len(json) {
+		t.Errorf("Expected MessagePack to be more compact than JSON, got %d and %d bytes", len(data), len(json))
+	}
+
+	// Output is deterministic
+	for i := 0; i < 5; i++ {
+		again, _ := collection.New[string, int]().Set("c", 3).Set("a", 1).Set("b", 2).ToMsgpack()
+		if !bytes.Equal(again, data) {
+			t.Fatal("Expected equal collections to encode identically")
+		}
+	}
+
+	restored, err := collection.NewFromMsgpack[string, int](data)
+	if err != nil {
+		t.Fatalf("NewFromMsgpack returned error: %v", err)
+	}
+	if !restored.Equals(c) {
+		t.Errorf("Expected round-tripped collection to equal original, got keys %v", restored.Keys())
+	}
+
+	// It decodes as a plain map
+	var plain map[string]int
+	if err := msgpack.Unmarshal(data, &plain); err != nil || plain["b"] != 2 {
+		t.Errorf("Expected a MessagePack map, got %v, %v", plain, err)
+	}
+
+	if _, err := collection.NewFromMsgpack[string, int]([]byte{0xc1}); err == nil {
+		t.Error("NewFromMsgpack should fail on invalid input")
+	}
+}
+
+// TestCollectionMsgpackNonStringKeys tests MessagePack round-tripping with non-string keys
+func TestCollectionMsgpackNonStringKeys(t *testing.T) {
+	type point struct{ X, Y int }
+	c := collection.New[point, string]().Set(point{1, 2}, "a").Set(point{3, 4}, "b")
+
+	data, err := c.ToMsgpack()
+	if err != nil {
+		t.Fatalf("ToMsgpack returned error: %v", err)
+	}
+	restored, err := collection.NewFromMsgpack[point, string](data)
+	if err != nil {
+		t.Fatalf("NewFromMsgpack returned error: %v", err)
+	}
+	if val, _ := restored.Get(point{3, 4}); val != "b" || restored.Size() != 2 {
+		t.Errorf("Expected struct keys to round-trip, got %v", restored.Keys())
+	}
+
+	// As a struct field
+	type snapshot struct {
+		Items *collection.Collection[int, string]
+	}
+	in := snapshot{Items: collection.New[int, string]().Set(1, "one")}
+	encoded, err := msgpack.Marshal(in)
+	if err != nil {
+		t.Fatalf("msgpack.Marshal returned error: %v", err)
+	}
+	out := snapshot{Items: collection.New[int, string]()}
+	if err := msgpack.Unmarshal(encoded, &out); err != nil {
+		t.Fatalf("msgpack.Unmarshal returned error: %v", err)
+	}
+	if val, _ := out.Items.Get(1); val != "one" {
+		t.Errorf("Expected 1 = one, got %q", val)
+	}
+}
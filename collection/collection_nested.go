@@ -0,0 +1,42 @@
+package collection
+
+// NestedGet follows path through a tree of nested collections, where each segment but the last must hold
+// a *Collection[string, any], and returns the value at the end of the path.
+// Returns false if any segment is missing, an intermediate value is not a collection, or path is empty.
+func NestedGet(c *Collection[string, any], path ...string) (any, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	node := c
+	for _, segment := range path[:len(path)-1] {
+		next, ok := node.Get(segment)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = next.(*Collection[string, any]); !ok {
+			return nil, false
+		}
+	}
+	return node.Get(path[len(path)-1])
+}
+
+// NestedSet stores value at the end of path in a tree of nested collections, creating missing intermediate
+// *Collection[string, any] nodes along the way.
+// Returns false without storing anything if path is empty or an existing intermediate value is not a collection.
+func NestedSet(c *Collection[string, any], value any, path ...string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	node := c
+	for _, segment := range path[:len(path)-1] {
+		next := node.Ensure(segment, func(string, *Collection[string, any]) any {
+			return New[string, any]()
+		})
+		var ok bool
+		if node, ok = next.(*Collection[string, any]); !ok {
+			return false
+		}
+	}
+	node.Set(path[len(path)-1], value)
+	return true
+}
@@ -0,0 +1,65 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNestedGet tests the NestedGet function
+func TestNestedGet(t *testing.T) {
+	db := collection.New[string, any]().Set("host", "localhost").Set("port", 5432)
+	config := collection.New[string, any]().Set("db", db).Set("debug", true)
+
+	if val, ok := collection.NestedGet(config, "db", "host"); !ok || val != "localhost" {
+		t.Errorf("Expected db.host = localhost, got %v, %v", val, ok)
+	}
+	if val, ok := collection.NestedGet(config, "debug"); !ok || val != true {
+		t.Errorf("Expected debug = true, got %v, %v", val, ok)
+	}
+	if val, ok := collection.NestedGet(config, "db"); !ok || val != db {
+		t.Errorf("Expected the nested collection for db, got %v, %v", val, ok)
+	}
+	if _, ok := collection.NestedGet(config, "db", "user"); ok {
+		t.Error("NestedGet should return false for a missing leaf")
+	}
+	if _, ok := collection.NestedGet(config, "cache", "ttl"); ok {
+		t.Error("NestedGet should return false for a missing intermediate node")
+	}
+	if _, ok := collection.NestedGet(config, "debug", "level"); ok {
+		t.Error("NestedGet should return false when an intermediate value is not a collection")
+	}
+	if _, ok := collection.NestedGet(config); ok {
+		t.Error("NestedGet should return false for an empty path")
+	}
+}
+
+// TestNestedSet tests the NestedSet function
+func TestNestedSet(t *testing.T) {
+	config := collection.New[string, any]()
+
+	if !collection.NestedSet(config, 30, "cache", "redis", "ttl") {
+		t.Fatal("NestedSet should create missing intermediate nodes")
+	}
+	if val, ok := collection.NestedGet(config, "cache", "redis", "ttl"); !ok || val != 30 {
+		t.Errorf("Expected cache.redis.ttl = 30, got %v, %v", val, ok)
+	}
+
+	// Existing nodes are reused
+	collection.NestedSet(config, "localhost", "cache", "redis", "host")
+	redis, _ := collection.NestedGet(config, "cache", "redis")
+	if size := redis.(*collection.Collection[string, any]).Size(); size != 2 {
+		t.Errorf("Expected the redis node to hold 2 items, got %d", size)
+	}
+
+	config.Set("debug", true)
+	if collection.NestedSet(config, "verbose", "debug", "level") {
+		t.Error("NestedSet should return false when an intermediate value is not a collection")
+	}
+	if val, _ := config.Get("debug"); val != true {
+		t.Error("NestedSet should not replace a non-collection value")
+	}
+	if collection.NestedSet(config, 1) {
+		t.Error("NestedSet should return false for an empty path")
+	}
+}
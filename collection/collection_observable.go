@@ -0,0 +1,91 @@
+package collection
+
+import (
+	"sync"
+	"time"
+)
+
+// Event describes a mutation delivered to Observable subscribers.
+type Event[K comparable, V any] = CollectionEvent[K, V]
+
+// Observable fans out the mutations of a collection to subscribers, each with its own buffered channel and goroutine.
+// It embeds the collection, so mutations can be made through the Observable or the collection directly.
+type Observable[K comparable, V any] struct {
+	*Collection[K, V]
+
+	mu          sync.RWMutex
+	subscribers map[chan Event[K, V]]struct{}
+	onOverflow  func(Event[K, V])
+}
+
+// Observe returns an Observable for c. Every change to c from then on is delivered to its subscribers.
+func Observe[K comparable, V any](c *Collection[K, V]) *Observable[K, V] {
+	o := &Observable[K, V]{
+		Collection:  c,
+		subscribers: make(map[chan Event[K, V]]struct{}),
+	}
+	c.OnSet(func(key K, oldValue *V, newValue V) {
+		event := Event[K, V]{Type: EventSet, Key: key, NewValue: newValue, Timestamp: time.Now()}
+		if oldValue != nil {
+			event.OldValue = *oldValue
+		}
+		o.publish(event)
+	})
+	c.OnDelete(func(key K, value V) {
+		o.publish(Event[K, V]{Type: EventDelete, Key: key, OldValue: value, Timestamp: time.Now()})
+	})
+	c.OnClear(func(int) {
+		o.publish(Event[K, V]{Type: EventClear, Timestamp: time.Now()})
+	})
+	return o
+}
+
+// Subscribe calls fn for every event, in order, from a dedicated goroutine, and returns a function that unsubscribes.
+// Events are buffered per subscriber; if fn falls behind and its buffer is full, further events for it are dropped
+// and passed to the OnOverflow callback instead of blocking the mutation.
+// Events already buffered when unsubscribing are still delivered.
+func (o *Observable[K, V]) Subscribe(fn func(Event[K, V])) func() {
+	ch := make(chan Event[K, V], watchBufferSize)
+	o.mu.Lock()
+	o.subscribers[ch] = struct{}{}
+	o.mu.Unlock()
+
+	go func() {
+		for event := range ch {
+			fn(event)
+		}
+	}()
+
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if _, ok := o.subscribers[ch]; ok {
+			delete(o.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// OnOverflow sets the callback called with each event dropped because a subscriber's buffer was full, and returns o.
+// It runs on the goroutine making the mutation, so it should return quickly.
+func (o *Observable[K, V]) OnOverflow(fn func(Event[K, V])) *Observable[K, V] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onOverflow = fn
+	return o
+}
+
+// publish sends event to every subscriber without blocking.
+func (o *Observable[K, V]) publish(event Event[K, V]) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for ch := range o.subscribers {
+		select {
+		case ch <- event:
+		default:
+			if o.onOverflow != nil {
+				o.onOverflow(event)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package collection_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestObserve tests the Observe function
+func TestObserve(t *testing.T) {
+	obs := collection.Observe(collection.New[string, int]())
+
+	var mu sync.Mutex
+	var first, second []collection.Event[string, int]
+	var wg sync.WaitGroup
+	wg.Add(6)
+	unsubscribe := obs.Subscribe(func(e collection.Event[string, int]) {
+		mu.Lock()
+		first = append(first, e)
+		mu.Unlock()
+		wg.Done()
+	})
+	defer unsubscribe()
+	defer obs.Subscribe(func(e collection.Event[string, int]) {
+		mu.Lock()
+		second = append(second, e)
+		mu.Unlock()
+		wg.Done()
+	})()
+
+	obs.Set("a", 1)
+	obs.Set("a", 2)
+	obs.Delete("a")
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(first) != 3 || len(second) != 3 {
+		t.Fatalf("Expected 3 events per subscriber, got %d and %d", len(first), len(second))
+	}
+	if e := first[1]; e.Type != collection.EventSet || e.OldValue != 1 || e.NewValue != 2 || e.Timestamp.IsZero() {
+		t.Errorf("Expected set a 1 -> 2, got %+v", e)
+	}
+	if e := first[2]; e.Type != collection.EventDelete || e.Key != "a" || e.OldValue != 2 {
+		t.Errorf("Expected delete a with old value 2, got %+v", e)
+	}
+}
+
+// TestObservableUnsubscribe tests that unsubscribing stops delivery
+func TestObservableUnsubscribe(t *testing.T) {
+	obs := collection.Observe(collection.New[string, int]())
+
+	var count atomic.Int32
+	unsubscribe := obs.Subscribe(func(collection.Event[string, int]) { count.Add(1) })
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	obs.Set("a", 1)
+	time.Sleep(10 * time.Millisecond)
+	if n := count.Load(); n != 0 {
+		t.Errorf("Expected no events after unsubscribing, got %d", n)
+	}
+}
+
+// TestObservableOnOverflow tests the OnOverflow method
+func TestObservableOnOverflow(t *testing.T) {
+	c := collection.New[int, int]()
+	obs := collection.Observe(c)
+
+	var dropped atomic.Int32
+	obs.OnOverflow(func(collection.Event[int, int]) { dropped.Add(1) })
+
+	release := make(chan struct{})
+	defer obs.Subscribe(func(collection.Event[int, int]) { <-release })()
+	defer close(release)
+
+	// Mutations on the underlying collection are observed too and never block on the slow subscriber
+	for i := 0; i < 200; i++ {
+		c.Set(i, i)
+	}
+	if dropped.Load() == 0 {
+		t.Error("Expected events to overflow the slow subscriber's buffer")
+	}
+}
@@ -0,0 +1,81 @@
+package collection
+
+import "slices"
+
+// PipelineBuilder accumulates transformation steps over a collection and applies them in order when Execute is called.
+// Between steps, items keep the order established by earlier steps, so Sort followed by Take selects the first n sorted items.
+// A PipelineBuilder can be executed any number of times; each run reads the current state of the source collection.
+type PipelineBuilder[K comparable, V any] struct {
+	source *Collection[K, V]
+	steps  []func(entries []Entry[K, V]) []Entry[K, V]
+}
+
+// Pipeline starts a lazy transformation pipeline over c. No work is done until Execute is called.
+func Pipeline[K comparable, V any](c *Collection[K, V]) *PipelineBuilder[K, V] {
+	return &PipelineBuilder[K, V]{source: c}
+}
+
+// Filter adds a step that keeps only the items for which fn returns true.
+func (p *PipelineBuilder[K, V]) Filter(fn func(value V, key K) bool) *PipelineBuilder[K, V] {
+	return p.then(func(entries []Entry[K, V]) []Entry[K, V] {
+		return slices.DeleteFunc(entries, func(e Entry[K, V]) bool {
+			return !fn(e.Value, e.Key)
+		})
+	})
+}
+
+// Sort adds a step that orders the items by compare.
+func (p *PipelineBuilder[K, V]) Sort(compare Comparator[K, V]) *PipelineBuilder[K, V] {
+	return p.then(func(entries []Entry[K, V]) []Entry[K, V] {
+		slices.SortStableFunc(entries, func(a, b Entry[K, V]) int {
+			return compare(a.Value, b.Value, a.Key, b.Key)
+		})
+		return entries
+	})
+}
+
+// Take adds a step that keeps only the first n items. A negative n keeps nothing.
+func (p *PipelineBuilder[K, V]) Take(n int) *PipelineBuilder[K, V] {
+	return p.then(func(entries []Entry[K, V]) []Entry[K, V] {
+		return entries[:max(0, min(n, len(entries)))]
+	})
+}
+
+// Skip adds a step that drops the first n items. A negative n drops nothing.
+func (p *PipelineBuilder[K, V]) Skip(n int) *PipelineBuilder[K, V] {
+	return p.then(func(entries []Entry[K, V]) []Entry[K, V] {
+		return entries[max(0, min(n, len(entries))):]
+	})
+}
+
+// MapValues adds a step that replaces each value with the result of fn.
+func (p *PipelineBuilder[K, V]) MapValues(fn func(value V, key K) V) *PipelineBuilder[K, V] {
+	return p.then(func(entries []Entry[K, V]) []Entry[K, V] {
+		for i := range entries {
+			entries[i].Value = fn(entries[i].Value, entries[i].Key)
+		}
+		return entries
+	})
+}
+
+// Execute applies all steps in order to a snapshot of the source collection and returns the result as a new collection.
+// Steps run with the source's lock released.
+func (p *PipelineBuilder[K, V]) Execute() *Collection[K, V] {
+	entries := p.source.TypedEntries()
+	for _, step := range p.steps {
+		entries = step(entries)
+	}
+	res := New[K, V]()
+	for _, e := range entries {
+		res.items[e.Key] = e.Value
+	}
+	return res
+}
+
+// then returns a new builder with step appended, leaving p unchanged so partial pipelines can be reused.
+func (p *PipelineBuilder[K, V]) then(step func(entries []Entry[K, V]) []Entry[K, V]) *PipelineBuilder[K, V] {
+	return &PipelineBuilder[K, V]{
+		source: p.source,
+		steps:  append(slices.Clip(p.steps), step),
+	}
+}
@@ -0,0 +1,80 @@
+package collection_test
+
+import (
+	"cmp"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestPipeline tests composing and executing a pipeline
+func TestPipeline(t *testing.T) {
+	c := collection.New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		c.Set(key, i+1)
+	}
+
+	byValue := func(v1, v2 int, k1, k2 string) int { return cmp.Compare(v1, v2) }
+	result := collection.Pipeline(c).
+		Filter(func(value int, key string) bool { return value%2 == 0 }).
+		Sort(byValue).
+		Skip(1).
+		Take(1).
+		MapValues(func(value int, key string) int { return value * 10 }).
+		Execute()
+
+	if result.Size() != 1 {
+		t.Fatalf("Expected 1 item, got %d", result.Size())
+	}
+	if val, ok := result.Get("d"); !ok || val != 40 {
+		t.Errorf("Expected d=40, got %d (ok=%v)", val, ok)
+	}
+	if c.Size() != 6 {
+		t.Error("Pipeline should not modify the source collection")
+	}
+}
+
+// TestPipelineLazy tests that steps run only on Execute and reflect the source at that time
+func TestPipelineLazy(t *testing.T) {
+	c := collection.New[string, int]()
+	calls := 0
+	p := collection.Pipeline(c).Filter(func(value int, key string) bool {
+		calls++
+		return true
+	})
+	if calls != 0 {
+		t.Error("Steps should not run before Execute")
+	}
+
+	c.Set("a", 1).Set("b", 2)
+	if result := p.Execute(); result.Size() != 2 || calls != 2 {
+		t.Errorf("Expected 2 items and 2 calls, got %d items and %d calls", result.Size(), calls)
+	}
+}
+
+// TestPipelineReuse tests that extending a pipeline does not affect the original
+func TestPipelineReuse(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+	byKey := func(v1, v2 int, k1, k2 string) int { return cmp.Compare(k1, k2) }
+
+	sorted := collection.Pipeline(c).Sort(byKey)
+	first := sorted.Take(1)
+	rest := sorted.Skip(1)
+
+	if keys := first.Execute().Keys(); !reflect.DeepEqual(keys, []string{"a"}) {
+		t.Errorf("Expected [a], got %v", keys)
+	}
+	keys := rest.Execute().Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"b", "c"}) {
+		t.Errorf("Expected [b c], got %v", keys)
+	}
+	if size := sorted.Take(-1).Execute().Size(); size != 0 {
+		t.Errorf("Take with a negative count should keep nothing, got %d", size)
+	}
+	if size := sorted.Skip(10).Execute().Size(); size != 0 {
+		t.Errorf("Skip past the end should keep nothing, got %d", size)
+	}
+}
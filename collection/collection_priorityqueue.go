@@ -0,0 +1,128 @@
+package collection
+
+import "container/heap"
+
+// PriorityQueue is a keyed priority queue backed by a Collection.
+// Entries are dequeued in the order defined by its Comparator: an entry that compares lower comes out first.
+// It is safe for concurrent use.
+type PriorityQueue[K comparable, V any] struct {
+	items *Collection[K, V]
+	heap  pqHeap[K, V] // guarded by items.mu
+}
+
+// pqHeap implements heap.Interface over the keys of a PriorityQueue, tracking each key's position.
+type pqHeap[K comparable, V any] struct {
+	keys    []K
+	index   map[K]int
+	items   map[K]V
+	compare Comparator[K, V]
+}
+
+func (h *pqHeap[K, V]) Len() int { return len(h.keys) }
+
+func (h *pqHeap[K, V]) Less(i, j int) bool {
+	a, b := h.keys[i], h.keys[j]
+	return h.compare(h.items[a], h.items[b], a, b) < 0
+}
+
+func (h *pqHeap[K, V]) Swap(i, j int) {
+	h.keys[i], h.keys[j] = h.keys[j], h.keys[i]
+	h.index[h.keys[i]] = i
+	h.index[h.keys[j]] = j
+}
+
+func (h *pqHeap[K, V]) Push(x any) {
+	key := x.(K)
+	h.index[key] = len(h.keys)
+	h.keys = append(h.keys, key)
+}
+
+func (h *pqHeap[K, V]) Pop() any {
+	n := len(h.keys) - 1
+	key := h.keys[n]
+	var zero K
+	h.keys[n] = zero
+	h.keys = h.keys[:n]
+	delete(h.index, key)
+	return key
+}
+
+// NewPriorityQueue creates a new empty PriorityQueue ordered by compare.
+func NewPriorityQueue[K comparable, V any](compare Comparator[K, V]) *PriorityQueue[K, V] {
+	items := New[K, V]()
+	return &PriorityQueue[K, V]{
+		items: items,
+		heap: pqHeap[K, V]{
+			index:   make(map[K]int),
+			items:   items.items,
+			compare: compare,
+		},
+	}
+}
+
+// Enqueue adds an entry to the queue. If the key is already queued, its value is replaced and its position updated.
+func (q *PriorityQueue[K, V]) Enqueue(key K, value V) *PriorityQueue[K, V] {
+	q.items.mu.Lock()
+	defer q.items.mu.Unlock()
+	q.items.items[key] = value
+	if i, ok := q.heap.index[key]; ok {
+		heap.Fix(&q.heap, i)
+	} else {
+		heap.Push(&q.heap, key)
+	}
+	return q
+}
+
+// Dequeue removes and returns the entry with the highest priority.
+// The third return value is false if the queue is empty.
+func (q *PriorityQueue[K, V]) Dequeue() (K, V, bool) {
+	q.items.mu.Lock()
+	defer q.items.mu.Unlock()
+	if q.heap.Len() == 0 {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	key := heap.Pop(&q.heap).(K)
+	value := q.items.items[key]
+	delete(q.items.items, key)
+	return key, value, true
+}
+
+// Peek returns the entry with the highest priority without removing it.
+// The third return value is false if the queue is empty.
+func (q *PriorityQueue[K, V]) Peek() (K, V, bool) {
+	q.items.mu.RLock()
+	defer q.items.mu.RUnlock()
+	if q.heap.Len() == 0 {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	key := q.heap.keys[0]
+	return key, q.items.items[key], true
+}
+
+// UpdatePriority replaces the value of a queued key and restores heap order.
+// Returns false if the key is not in the queue.
+func (q *PriorityQueue[K, V]) UpdatePriority(key K, value V) bool {
+	q.items.mu.Lock()
+	defer q.items.mu.Unlock()
+	i, ok := q.heap.index[key]
+	if !ok {
+		return false
+	}
+	q.items.items[key] = value
+	heap.Fix(&q.heap, i)
+	return true
+}
+
+// Size returns the number of entries in the queue.
+func (q *PriorityQueue[K, V]) Size() int {
+	return q.items.Size()
+}
+
+// IsEmpty reports whether the queue holds no entries.
+func (q *PriorityQueue[K, V]) IsEmpty() bool {
+	return q.Size() == 0
+}
@@ -0,0 +1,75 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+func byPriority(a, b int, _, _ string) int {
+	return a - b
+}
+
+// TestPriorityQueue tests priority ordering of PriorityQueue
+func TestPriorityQueue(t *testing.T) {
+	q := collection.NewPriorityQueue[string, int](byPriority)
+
+	if _, _, ok := q.Dequeue(); ok {
+		t.Error("Dequeue on an empty queue should return false")
+	}
+	if _, _, ok := q.Peek(); ok {
+		t.Error("Peek on an empty queue should return false")
+	}
+
+	q.Enqueue("low", 5).Enqueue("high", 1).Enqueue("mid", 3).Enqueue("lowest", 9)
+	if q.Size() != 4 {
+		t.Errorf("Expected size 4, got %d", q.Size())
+	}
+	if k, v, ok := q.Peek(); !ok || k != "high" || v != 1 {
+		t.Errorf("Expected Peek to return high=1, got %s=%d, %v", k, v, ok)
+	}
+
+	// Re-enqueueing a key replaces its priority
+	q.Enqueue("lowest", 0)
+
+	var got []string
+	for k, _, ok := q.Dequeue(); ok; k, _, ok = q.Dequeue() {
+		got = append(got, k)
+	}
+	want := []string{"lowest", "high", "mid", "low"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Error("Queue should be empty after dequeuing every entry")
+	}
+}
+
+// TestPriorityQueueUpdatePriority tests the UpdatePriority method
+func TestPriorityQueueUpdatePriority(t *testing.T) {
+	q := collection.NewPriorityQueue[string, int](byPriority)
+	q.Enqueue("a", 1).Enqueue("b", 2).Enqueue("c", 3)
+
+	if q.UpdatePriority("missing", 0) {
+		t.Error("UpdatePriority should return false for a missing key")
+	}
+	if !q.UpdatePriority("c", 0) {
+		t.Error("UpdatePriority should return true for a queued key")
+	}
+	if k, v, _ := q.Dequeue(); k != "c" || v != 0 {
+		t.Errorf("Expected c=0 first after raising its priority, got %s=%d", k, v)
+	}
+
+	q.UpdatePriority("a", 10)
+	if k, _, _ := q.Dequeue(); k != "b" {
+		t.Errorf("Expected b after lowering a's priority, got %s", k)
+	}
+	if k, _, _ := q.Dequeue(); k != "a" {
+		t.Errorf("Expected a last, got %s", k)
+	}
+}
@@ -0,0 +1,58 @@
+package collection
+
+// Queue is a first-in, first-out queue backed by a Collection keyed by insertion sequence.
+// It is safe for concurrent use.
+type Queue[V any] struct {
+	items *Collection[int, V]
+	head  int // next key to dequeue, guarded by items.mu
+	tail  int // next key to enqueue, guarded by items.mu
+}
+
+// NewQueue creates a new empty Queue.
+func NewQueue[V any]() *Queue[V] {
+	return &Queue[V]{items: New[int, V]()}
+}
+
+// Enqueue adds a value to the back of the queue.
+// The sequence number is taken and the value stored under the same lock, so Dequeue never sees a gap.
+func (q *Queue[V]) Enqueue(value V) *Queue[V] {
+	q.items.mu.Lock()
+	defer q.items.mu.Unlock()
+	q.items.items[q.tail] = value
+	q.tail++
+	return q
+}
+
+// Dequeue removes and returns the value at the front of the queue.
+// The second return value is false if the queue is empty.
+func (q *Queue[V]) Dequeue() (V, bool) {
+	q.items.mu.Lock()
+	defer q.items.mu.Unlock()
+	value, ok := q.items.items[q.head]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	delete(q.items.items, q.head)
+	q.head++
+	return value, true
+}
+
+// Peek returns the value at the front of the queue without removing it.
+// The second return value is false if the queue is empty.
+func (q *Queue[V]) Peek() (V, bool) {
+	q.items.mu.RLock()
+	defer q.items.mu.RUnlock()
+	value, ok := q.items.items[q.head]
+	return value, ok
+}
+
+// Size returns the number of values in the queue.
+func (q *Queue[V]) Size() int {
+	return q.items.Size()
+}
+
+// IsEmpty reports whether the queue holds no values.
+func (q *Queue[V]) IsEmpty() bool {
+	return q.Size() == 0
+}
@@ -0,0 +1,117 @@
+package collection_test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestQueue tests FIFO ordering of Queue
+func TestQueue(t *testing.T) {
+	q := collection.NewQueue[string]()
+
+	if !q.IsEmpty() {
+		t.Error("New queue should be empty")
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Error("Dequeue on an empty queue should return false")
+	}
+	if _, ok := q.Peek(); ok {
+		t.Error("Peek on an empty queue should return false")
+	}
+
+	q.Enqueue("a").Enqueue("b").Enqueue("c")
+	if q.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", q.Size())
+	}
+	if v, ok := q.Peek(); !ok || v != "a" {
+		t.Errorf("Expected Peek to return a, got %q, %v", v, ok)
+	}
+	if q.Size() != 3 {
+		t.Error("Peek should not remove the value")
+	}
+
+	for _, want := range []string{"a", "b"} {
+		if v, ok := q.Dequeue(); !ok || v != want {
+			t.Errorf("Expected Dequeue to return %q, got %q, %v", want, v, ok)
+		}
+	}
+
+	q.Enqueue("d")
+	for _, want := range []string{"c", "d"} {
+		if v, ok := q.Dequeue(); !ok || v != want {
+			t.Errorf("Expected Dequeue to return %q, got %q, %v", want, v, ok)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Error("Queue should be empty after dequeuing every value")
+	}
+}
+
+// TestQueueConcurrent tests concurrent use of Queue
+func TestQueueConcurrent(t *testing.T) {
+	q := collection.NewQueue[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			q.Enqueue(n)
+		}(i)
+	}
+	wg.Wait()
+
+	got := make([]int, 0, 100)
+	for v, ok := q.Dequeue(); ok; v, ok = q.Dequeue() {
+		got = append(got, v)
+	}
+	if len(got) != 100 {
+		t.Fatalf("Expected 100 values, got %d", len(got))
+	}
+	sort.Ints(got)
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("Expected every value exactly once, got %v", got)
+		}
+	}
+}
+
+// TestQueueConcurrentDequeue tests that values enqueued while others are dequeued are never skipped
+func TestQueueConcurrentDequeue(t *testing.T) {
+	q := collection.NewQueue[int]()
+	const producers, perProducer = 8, 500
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Enqueue(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	seen := make(map[int]bool)
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for finished := false; !finished; {
+		select {
+		case <-done:
+			finished = true
+		default:
+		}
+		for v, ok := q.Dequeue(); ok; v, ok = q.Dequeue() {
+			seen[v] = true
+		}
+	}
+	if len(seen) != producers*perProducer {
+		t.Errorf("Expected %d values, got %d", producers*perProducer, len(seen))
+	}
+}
@@ -0,0 +1,63 @@
+package collection
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrTooManyConflicts is returned by RetryOnConflict when every attempt lost a race with a concurrent update.
+var ErrTooManyConflicts = errors.New("collection: too many conflicting updates")
+
+// Backoff bounds for RetryOnConflict.
+const (
+	retryInitialBackoff = time.Millisecond
+	retryMaxBackoff     = 100 * time.Millisecond
+)
+
+// RetryOnConflict performs an optimistic update of key. It reads the current value, calls fn without any lock held,
+// and stores the result only if the item has not changed in the meantime. The update goes through directly if the
+// collection was not written to at all since the read; otherwise the item is compared with reflect.DeepEqual, so
+// values that never equal themselves, such as NaN, conflict only when there was a concurrent write.
+// On conflict it retries up to maxRetries times with exponential backoff, then returns ErrTooManyConflicts.
+// If fn returns false, the update is abandoned and RetryOnConflict returns nil.
+func (c *Collection[K, V]) RetryOnConflict(key K, fn func(current V, exists bool) (V, bool), maxRetries int) error {
+	key = c.normalizeKey(key)
+	backoff := retryInitialBackoff
+	for attempt := 0; ; attempt++ {
+		c.mu.RLock()
+		current, exists := c.items[key]
+		gen := c.writeGen
+		c.mu.RUnlock()
+
+		next, ok := fn(current, exists)
+		if !ok {
+			return nil
+		}
+		if c.compareAndSwap(key, gen, current, exists, next) {
+			return nil
+		}
+		if attempt >= maxRetries {
+			return ErrTooManyConflicts
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// compareAndSwap stores next for key, like Set, if the collection is still at write generation gen or, failing that,
+// the item is still in the expected state. Returns false, leaving the collection unchanged, if neither holds.
+func (c *Collection[K, V]) compareAndSwap(key K, gen uint64, expected V, expectedExists bool, next V) bool {
+	swapped := false
+	c.write(func(b *writeBatch[K, V]) {
+		if c.writeGen != gen {
+			old, existed := b.get(key)
+			if existed != expectedExists || (existed && !reflect.DeepEqual(old, expected)) {
+				return
+			}
+		}
+		b.set(key, next)
+		swapped = true
+	})
+	return swapped
+}
@@ -0,0 +1,85 @@
+package collection_test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionRetryOnConflict tests optimistic updates
+func TestCollectionRetryOnConflict(t *testing.T) {
+	c := collection.New[string, int]()
+	increment := func(current int, exists bool) (int, bool) {
+		return current + 1, true
+	}
+
+	if err := c.RetryOnConflict("hits", increment, 3); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+	if val, _ := c.Get("hits"); val != 1 {
+		t.Errorf("Expected 1, got %d", val)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.RetryOnConflict("hits", increment, 100); err != nil {
+				t.Errorf("Expected nil error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if val, _ := c.Get("hits"); val != 21 {
+		t.Errorf("Expected no lost updates (21), got %d", val)
+	}
+}
+
+// TestCollectionRetryOnConflictAbort tests abandoning an update
+func TestCollectionRetryOnConflictAbort(t *testing.T) {
+	c := collection.New[string, int]().Set("stock", 0)
+	err := c.RetryOnConflict("stock", func(current int, exists bool) (int, bool) {
+		return current - 1, current > 0
+	}, 3)
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if val, _ := c.Get("stock"); val != 0 {
+		t.Errorf("Aborted update should not change the value, got %d", val)
+	}
+}
+
+// TestCollectionRetryOnConflictExhausted tests giving up after repeated conflicts
+func TestCollectionRetryOnConflictExhausted(t *testing.T) {
+	c := collection.New[string, int]().Set("key", 0)
+	calls := 0
+	err := c.RetryOnConflict("key", func(current int, exists bool) (int, bool) {
+		calls++
+		c.Set("key", current+100) // a concurrent writer always wins
+		return current + 1, true
+	}, 2)
+	if !errors.Is(err, collection.ErrTooManyConflicts) {
+		t.Errorf("Expected ErrTooManyConflicts, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 1 attempt and 2 retries, got %d calls", calls)
+	}
+}
+
+// TestCollectionRetryOnConflictNaN tests updating a value that never equals itself
+func TestCollectionRetryOnConflictNaN(t *testing.T) {
+	c := collection.New[string, float64]().Set("ratio", math.NaN())
+	err := c.RetryOnConflict("ratio", func(current float64, exists bool) (float64, bool) {
+		return 1, true
+	}, 2)
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if val, _ := c.Get("ratio"); val != 1 {
+		t.Errorf("Expected 1, got %v", val)
+	}
+}
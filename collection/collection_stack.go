@@ -0,0 +1,56 @@
+package collection
+
+// Stack is a last-in, first-out stack backed by a Collection keyed by stack depth.
+// It is safe for concurrent use.
+type Stack[V any] struct {
+	items *Collection[int, V]
+	top   int // key of the next value to push, guarded by items.mu
+}
+
+// NewStack creates a new empty Stack.
+func NewStack[V any]() *Stack[V] {
+	return &Stack[V]{items: New[int, V]()}
+}
+
+// Push adds a value to the top of the stack.
+func (s *Stack[V]) Push(value V) *Stack[V] {
+	s.items.mu.Lock()
+	defer s.items.mu.Unlock()
+	s.items.items[s.top] = value
+	s.top++
+	return s
+}
+
+// Pop removes and returns the value at the top of the stack, the one with the highest key.
+// The second return value is false if the stack is empty.
+func (s *Stack[V]) Pop() (V, bool) {
+	s.items.mu.Lock()
+	defer s.items.mu.Unlock()
+	if s.top == 0 {
+		var zero V
+		return zero, false
+	}
+	s.top--
+	value := s.items.items[s.top]
+	delete(s.items.items, s.top)
+	return value, true
+}
+
+// Peek returns the value at the top of the stack without removing it.
+// The second return value is false if the stack is empty.
+func (s *Stack[V]) Peek() (V, bool) {
+	s.items.mu.RLock()
+	defer s.items.mu.RUnlock()
+	value, ok := s.items.items[s.top-1]
+	return value, ok
+}
+
+// Size returns the number of values in the stack.
+func (s *Stack[V]) Size() int {
+	return s.items.Size()
+}
+
+// IsEmpty reports whether the stack holds no values.
+func (s *Stack[V]) IsEmpty() bool {
+	return s.Size() == 0
+}
@@ -0,0 +1,74 @@
+package collection_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestStack tests LIFO ordering of Stack
+func TestStack(t *testing.T) {
+	s := collection.NewStack[string]()
+
+	if !s.IsEmpty() {
+		t.Error("New stack should be empty")
+	}
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop on an empty stack should return false")
+	}
+	if _, ok := s.Peek(); ok {
+		t.Error("Peek on an empty stack should return false")
+	}
+
+	s.Push("a").Push("b").Push("c")
+	if s.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", s.Size())
+	}
+	if v, ok := s.Peek(); !ok || v != "c" {
+		t.Errorf("Expected Peek to return c, got %q, %v", v, ok)
+	}
+	if s.Size() != 3 {
+		t.Error("Peek should not remove the value")
+	}
+
+	if v, ok := s.Pop(); !ok || v != "c" {
+		t.Errorf("Expected Pop to return c, got %q, %v", v, ok)
+	}
+	s.Push("d")
+	for _, want := range []string{"d", "b", "a"} {
+		if v, ok := s.Pop(); !ok || v != want {
+			t.Errorf("Expected Pop to return %q, got %q, %v", want, v, ok)
+		}
+	}
+	if !s.IsEmpty() {
+		t.Error("Stack should be empty after popping every value")
+	}
+}
+
+// TestStackConcurrent tests concurrent use of Stack
+func TestStackConcurrent(t *testing.T) {
+	s := collection.NewStack[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			s.Push(n)
+		}(i)
+		go func() {
+			defer wg.Done()
+			s.Pop()
+		}()
+	}
+	wg.Wait()
+
+	popped := 0
+	for _, ok := s.Pop(); ok; _, ok = s.Pop() {
+		popped++
+	}
+	if !s.IsEmpty() || popped > 100 {
+		t.Errorf("Expected an empty stack after draining, popped %d", popped)
+	}
+}
@@ -0,0 +1,111 @@
+package collection
+
+import (
+	"math"
+	"slices"
+)
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint that permits any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// MeanCollection returns the arithmetic mean of all values, or false if the collection is empty.
+func MeanCollection[K comparable, V Number](c *Collection[K, V]) (float64, bool) {
+	return MeanCollectionBy(c, func(value V) float64 {
+		return float64(value)
+	})
+}
+
+// MeanCollectionBy returns the arithmetic mean of the values projected by selector, or false if the collection is empty.
+func MeanCollectionBy[K comparable, V any](c *Collection[K, V], selector func(value V) float64) (float64, bool) {
+	values := c.Values()
+	if len(values) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += selector(v)
+	}
+	return sum / float64(len(values)), true
+}
+
+// VarianceCollection returns the population variance of all values, computed with Welford's online algorithm.
+// Returns false if the collection has fewer than 2 values.
+func VarianceCollection[K comparable, V Float](c *Collection[K, V]) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.items) < 2 {
+		return 0, false
+	}
+	var mean, m2 float64
+	n := 0
+	for _, v := range c.items {
+		n++
+		x := float64(v)
+		delta := x - mean
+		mean += delta / float64(n)
+		m2 += delta * (x - mean)
+	}
+	return V(m2 / float64(n)), true
+}
+
+// StdDevCollection returns the population standard deviation of all values.
+// Returns false if the collection has fewer than 2 values.
+func StdDevCollection[K comparable, V Float](c *Collection[K, V]) (V, bool) {
+	variance, ok := VarianceCollection(c)
+	if !ok {
+		return 0, false
+	}
+	return V(math.Sqrt(float64(variance))), true
+}
+
+// ProductCollection returns the product of all values, or 1 if the collection is empty.
+func ProductCollection[K comparable, V Number](c *Collection[K, V]) V {
+	return ProductCollectionBy(c, func(value V) V {
+		return value
+	})
+}
+
+// ProductCollectionBy returns the product of the values projected by selector, or 1 if the collection is empty.
+func ProductCollectionBy[K comparable, V any, N Number](c *Collection[K, V], selector func(value V) N) N {
+	product := N(1)
+	for _, v := range c.Values() {
+		product *= selector(v)
+	}
+	return product
+}
+
+// MedianCollection returns the median value, or false if the collection is empty.
+// For an even number of values it returns the lower of the two middle values.
+func MedianCollection[K comparable, V Number](c *Collection[K, V]) (V, bool) {
+	values := c.Values()
+	if len(values) == 0 {
+		return 0, false
+	}
+	slices.Sort(values)
+	return values[(len(values)-1)/2], true
+}
+
+// PercentileCollection returns the p-th percentile of all values, where p is between 0 and 1, using the nearest-rank method.
+// Returns false if the collection is empty or p is out of range.
+func PercentileCollection[K comparable, V Float](c *Collection[K, V], p float64) (V, bool) {
+	values := c.Values()
+	if len(values) == 0 || p < 0 || p > 1 {
+		return 0, false
+	}
+	slices.Sort(values)
+	rank := int(math.Ceil(p * float64(len(values))))
+	return values[max(rank-1, 0)], true
+}
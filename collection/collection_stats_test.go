@@ -0,0 +1,136 @@
+package collection_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestMeanCollection tests the MeanCollection function
+func TestMeanCollection(t *testing.T) {
+	c := collection.New[string, int]()
+	if _, ok := collection.MeanCollection(c); ok {
+		t.Error("MeanCollection on empty collection should return false")
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 4)
+	mean, ok := collection.MeanCollection(c)
+	if !ok || mean != 7.0/3.0 {
+		t.Errorf("Expected mean %v, got %v (ok=%v)", 7.0/3.0, mean, ok)
+	}
+
+	f := collection.New[string, float64]().Set("x", 1.5).Set("y", 2.5)
+	if mean, _ := collection.MeanCollection(f); mean != 2 {
+		t.Errorf("Expected mean 2, got %v", mean)
+	}
+}
+
+// TestMeanCollectionBy tests the MeanCollectionBy function
+func TestMeanCollectionBy(t *testing.T) {
+	type Order struct {
+		Total float64
+	}
+	c := collection.New[string, Order]()
+	total := func(o Order) float64 { return o.Total }
+
+	if _, ok := collection.MeanCollectionBy(c, total); ok {
+		t.Error("MeanCollectionBy on empty collection should return false")
+	}
+
+	c.Set("a", Order{10}).Set("b", Order{20}).Set("c", Order{60})
+	mean, ok := collection.MeanCollectionBy(c, total)
+	if !ok || mean != 30 {
+		t.Errorf("Expected mean 30, got %v (ok=%v)", mean, ok)
+	}
+}
+
+// TestVarianceCollection tests the VarianceCollection and StdDevCollection functions
+func TestVarianceCollection(t *testing.T) {
+	c := collection.New[string, float64]().Set("a", 2)
+	if _, ok := collection.VarianceCollection(c); ok {
+		t.Error("VarianceCollection with fewer than 2 values should return false")
+	}
+	if _, ok := collection.StdDevCollection(c); ok {
+		t.Error("StdDevCollection with fewer than 2 values should return false")
+	}
+
+	values := []float64{4, 4, 4, 5, 5, 7, 9}
+	for i, v := range values {
+		c.Set(string(rune('b'+i)), v)
+	}
+
+	variance, ok := collection.VarianceCollection(c)
+	if !ok || math.Abs(variance-4) > 1e-9 {
+		t.Errorf("Expected variance 4, got %v (ok=%v)", variance, ok)
+	}
+	stddev, ok := collection.StdDevCollection(c)
+	if !ok || math.Abs(stddev-2) > 1e-9 {
+		t.Errorf("Expected standard deviation 2, got %v (ok=%v)", stddev, ok)
+	}
+}
+
+// TestProductCollection tests the ProductCollection and ProductCollectionBy functions
+func TestProductCollection(t *testing.T) {
+	c := collection.New[string, int]()
+	if product := collection.ProductCollection(c); product != 1 {
+		t.Errorf("Expected product 1 for empty collection, got %d", product)
+	}
+
+	c.Set("a", 2).Set("b", 3).Set("c", 4)
+	if product := collection.ProductCollection(c); product != 24 {
+		t.Errorf("Expected product 24, got %d", product)
+	}
+
+	type Factor struct {
+		Growth float64
+	}
+	f := collection.New[string, Factor]().Set("q1", Factor{1.5}).Set("q2", Factor{2})
+	growth := collection.ProductCollectionBy(f, func(factor Factor) float64 { return factor.Growth })
+	if growth != 3 {
+		t.Errorf("Expected compound growth 3, got %v", growth)
+	}
+}
+
+// TestMedianCollection tests the MedianCollection function
+func TestMedianCollection(t *testing.T) {
+	if _, ok := collection.MedianCollection(collection.New[string, int]()); ok {
+		t.Error("MedianCollection on an empty collection should return false")
+	}
+
+	odd := collection.New[string, int]().Set("a", 7).Set("b", 1).Set("c", 4)
+	if median, ok := collection.MedianCollection(odd); !ok || median != 4 {
+		t.Errorf("Expected median 4, got %d, %v", median, ok)
+	}
+
+	even := collection.New[string, float64]().Set("a", 4).Set("b", 1).Set("c", 3).Set("d", 2)
+	if median, ok := collection.MedianCollection(even); !ok || median != 2 {
+		t.Errorf("Expected lower median 2, got %v, %v", median, ok)
+	}
+	if val, _ := even.Get("a"); val != 4 {
+		t.Error("MedianCollection should not modify the collection")
+	}
+}
+
+// TestPercentileCollection tests the PercentileCollection function
+func TestPercentileCollection(t *testing.T) {
+	if _, ok := collection.PercentileCollection(collection.New[int, float64](), 0.5); ok {
+		t.Error("PercentileCollection on an empty collection should return false")
+	}
+
+	c := collection.New[int, float64]()
+	for i := 1; i <= 10; i++ {
+		c.Set(i, float64(i*10))
+	}
+	for p, want := range map[float64]float64{0: 10, 0.1: 10, 0.5: 50, 0.9: 90, 0.95: 100, 1: 100} {
+		if got, ok := collection.PercentileCollection(c, p); !ok || got != want {
+			t.Errorf("Expected p%v = %v, got %v, %v", p*100, want, got, ok)
+		}
+	}
+	if _, ok := collection.PercentileCollection(c, 1.5); ok {
+		t.Error("PercentileCollection should return false for p > 1")
+	}
+	if _, ok := collection.PercentileCollection(c, -0.1); ok {
+		t.Error("PercentileCollection should return false for p < 0")
+	}
+}
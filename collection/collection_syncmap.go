@@ -0,0 +1,33 @@
+package collection
+
+import "sync"
+
+// NewFromSyncMap creates a new Collection from the entries of m. Entries whose key is not a K or whose value
+// is not a V are skipped. Entries stored or deleted concurrently with the call may or may not be included.
+func NewFromSyncMap[K comparable, V any](m *sync.Map) *Collection[K, V] {
+	c := New[K, V]()
+	m.Range(func(key, value any) bool {
+		k, ok := key.(K)
+		if !ok {
+			return true
+		}
+		v, ok := value.(V)
+		if !ok {
+			return true
+		}
+		c.items[k] = v
+		return true
+	})
+	return c
+}
+
+// ToSyncMap returns a new sync.Map holding a snapshot of the items.
+func (c *Collection[K, V]) ToSyncMap() *sync.Map {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m := &sync.Map{}
+	for k, v := range c.items {
+		m.Store(k, v)
+	}
+	return m
+}
@@ -0,0 +1,57 @@
+package collection_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestNewFromSyncMap tests the NewFromSyncMap function
+func TestNewFromSyncMap(t *testing.T) {
+	var m sync.Map
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("wrong value", "x")
+	m.Store(42, 3)
+
+	c := collection.NewFromSyncMap[string, int](&m)
+	if c.Size() != 2 {
+		t.Errorf("Expected 2 items, skipping mistyped entries, got %d", c.Size())
+	}
+	if val, _ := c.Get("b"); val != 2 {
+		t.Errorf("Expected b = 2, got %d", val)
+	}
+
+	if empty := collection.NewFromSyncMap[string, int](&sync.Map{}); empty.Size() != 0 {
+		t.Errorf("Expected an empty collection, got size %d", empty.Size())
+	}
+}
+
+// TestCollectionToSyncMap tests the ToSyncMap method
+func TestCollectionToSyncMap(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	m := c.ToSyncMap()
+	count := 0
+	m.Range(func(key, value any) bool {
+		count++
+		if want, _ := c.Get(key.(string)); value != want {
+			t.Errorf("Expected %v = %d, got %v", key, want, value)
+		}
+		return true
+	})
+	if count != 2 {
+		t.Errorf("Expected 2 entries, got %d", count)
+	}
+
+	// The sync.Map is a snapshot
+	c.Set("c", 3)
+	if _, ok := m.Load("c"); ok {
+		t.Error("ToSyncMap should not reflect later changes")
+	}
+
+	if round := collection.NewFromSyncMap[string, int](c.ToSyncMap()); !round.Equals(c) {
+		t.Errorf("Expected a round trip to preserve the items, got keys %v", round.Keys())
+	}
+}
@@ -0,0 +1,33 @@
+package collection
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Tabulate returns the items as a plain-text table with aligned columns, for terminals and log messages.
+// The first row holds keyHeader and valueHeader, followed by one row per item in ascending key order
+// (numbers and strings compare naturally, other types by their formatted form). Columns are separated by spaces.
+func (c *Collection[K, V]) Tabulate(keyHeader, valueHeader string) string {
+	c.mu.RLock()
+	keys := c.keysUnlocked()
+	slices.SortFunc(keys, func(a, b K) int {
+		return compareNatural(a, b)
+	})
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i] = c.items[k]
+	}
+	c.mu.RUnlock()
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", keyHeader, valueHeader)
+	for i, k := range keys {
+		fmt.Fprintf(w, "%v\t%v\n", k, values[i])
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionTabulate tests the Tabulate method
+func TestCollectionTabulate(t *testing.T) {
+	c := collection.New[string, int]().Set("charlie", 3).Set("alice", 100).Set("bob", 20)
+
+	want := "" +
+		"NAME     SCORE\n" +
+		"alice    100\n" +
+		"bob      20\n" +
+		"charlie  3\n"
+	if got := c.Tabulate("NAME", "SCORE"); got != want {
+		t.Errorf("Expected table:\n%s\ngot:\n%s", want, got)
+	}
+
+	numeric := collection.New[int, string]().Set(10, "ten").Set(2, "two").Set(1, "one")
+	want = "" +
+		"N   WORD\n" +
+		"1   one\n" +
+		"2   two\n" +
+		"10  ten\n"
+	if got := numeric.Tabulate("N", "WORD"); got != want {
+		t.Errorf("Expected numeric keys in natural order:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := collection.New[string, int]().Tabulate("KEY", "VALUE"); got != "KEY  VALUE\n" {
+		t.Errorf("Expected only the header for an empty collection, got %q", got)
+	}
+}
@@ -1,8 +1,12 @@
 package collection_test
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -22,6 +26,61 @@ func TestNewCollection(t *testing.T) {
 	}
 }
 
+// TestNewOf tests creating a collection from typed entries
+func TestNewOf(t *testing.T) {
+	empty := collection.NewOf[string, int]()
+	if empty.Size() != 0 {
+		t.Errorf("NewOf without entries should be empty, got size %d", empty.Size())
+	}
+
+	c := collection.NewOf(
+		collection.Entry[string, int]{Key: "a", Value: 1},
+		collection.Entry[string, int]{Key: "b", Value: 2},
+		collection.Entry[string, int]{Key: "a", Value: 3},
+	)
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+	if v, _ := c.Get("a"); v != 3 {
+		t.Errorf("Last entry should win for duplicate keys, got %d", v)
+	}
+	if v, _ := c.Get("b"); v != 2 {
+		t.Errorf("Expected b=2, got %d", v)
+	}
+}
+
+// TestNewFromSlice tests creating a collection from a slice
+func TestNewFromSlice(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+
+	empty := collection.NewFromSlice([]User{}, func(u User) int { return u.ID })
+	if empty.Size() != 0 {
+		t.Errorf("Collection from empty slice should be empty, got size %d", empty.Size())
+	}
+
+	users := []User{{1, "Alice"}, {2, "Bob"}, {1, "Alicia"}}
+	c := collection.NewFromSlice(users, func(u User) int { return u.ID })
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+	if u, _ := c.Get(1); u.Name != "Alicia" {
+		t.Errorf("Last item should win for duplicate keys, got %s", u.Name)
+	}
+
+	// Nil pointers are skipped
+	alice := &User{1, "Alice"}
+	ptrs := collection.NewFromSlice([]*User{alice, nil, {2, "Bob"}}, func(u *User) int { return u.ID })
+	if ptrs.Size() != 2 {
+		t.Errorf("Nil items should be skipped, got size %d", ptrs.Size())
+	}
+	if u, _ := ptrs.Get(1); u != alice {
+		t.Error("Pointer items should be stored as-is")
+	}
+}
+
 // TestCollectionSet tests the Set method
 func TestCollectionSet(t *testing.T) {
 	c := collection.New[string, int]()
@@ -243,6 +302,38 @@ func TestCollectionValues(t *testing.T) {
 	}
 }
 
+// TestCollectionKeysWhere tests the KeysWhere and ValuesWhere methods
+func TestCollectionKeysWhere(t *testing.T) {
+	c := collection.New[string, int]()
+	even := func(value int, key string) bool { return value%2 == 0 }
+
+	if keys := c.KeysWhere(even); keys == nil || len(keys) != 0 {
+		t.Errorf("KeysWhere on empty collection should return an empty slice, got %v", keys)
+	}
+	if values := c.ValuesWhere(even); values == nil || len(values) != 0 {
+		t.Errorf("ValuesWhere on empty collection should return an empty slice, got %v", values)
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+
+	keys := c.KeysWhere(even)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"b", "d"}) {
+		t.Errorf("Expected keys [b d], got %v", keys)
+	}
+
+	values := c.ValuesWhere(even)
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{2, 4}) {
+		t.Errorf("Expected values [2 4], got %v", values)
+	}
+
+	byKey := c.KeysWhere(func(value int, key string) bool { return key == "c" })
+	if !reflect.DeepEqual(byKey, []string{"c"}) {
+		t.Errorf("Predicate should receive keys, got %v", byKey)
+	}
+}
+
 // TestCollectionEntries tests the Entries method
 func TestCollectionEntries(t *testing.T) {
 	c := collection.New[string, int]()
@@ -396,6 +487,43 @@ func TestCollectionEnsure(t *testing.T) {
 	}
 }
 
+// TestCollectionSetDefault tests the SetDefault method
+func TestCollectionSetDefault(t *testing.T) {
+	c := collection.New[string, int]()
+
+	if v := c.SetDefault("a", 1); v != 1 {
+		t.Errorf("SetDefault on missing key should return the new value 1, got %d", v)
+	}
+	if v, _ := c.Get("a"); v != 1 {
+		t.Errorf("SetDefault should store the value, got %d", v)
+	}
+
+	if v := c.SetDefault("a", 2); v != 1 {
+		t.Errorf("SetDefault on existing key should return existing value 1, got %d", v)
+	}
+	if v, _ := c.Get("a"); v != 1 {
+		t.Errorf("SetDefault should not overwrite existing value, got %d", v)
+	}
+
+	// Concurrent callers all observe the same stored value
+	results := make([]int, 50)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			results[n] = c.SetDefault("shared", n)
+		}(i)
+	}
+	wg.Wait()
+	stored, _ := c.Get("shared")
+	for _, r := range results {
+		if r != stored {
+			t.Errorf("All callers should observe the stored value %d, got %d", stored, r)
+		}
+	}
+}
+
 // TestCollectionHasAll tests the HasAll method
 func TestCollectionHasAll(t *testing.T) {
 	c := collection.New[string, int]()
@@ -1461,6 +1589,77 @@ func TestCollectionEach(t *testing.T) {
 	}
 }
 
+// TestCollectionEachWithBreak tests the EachWithBreak method
+func TestCollectionEachWithBreak(t *testing.T) {
+	c := collection.New[string, int]()
+
+	called := 0
+	result := c.EachWithBreak(func(value int, key string, coll *collection.Collection[string, int]) bool {
+		called++
+		return true
+	})
+	if result != c {
+		t.Error("EachWithBreak should return the collection for chaining")
+	}
+	if called != 0 {
+		t.Errorf("Function should not be called on empty collection, called %d times", called)
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+
+	// Continue through all items
+	called = 0
+	c.EachWithBreak(func(value int, key string, coll *collection.Collection[string, int]) bool {
+		called++
+		return true
+	})
+	if called != 4 {
+		t.Errorf("Function should be called for every item, called %d times", called)
+	}
+
+	// Stop after the second item
+	called = 0
+	c.EachWithBreak(func(value int, key string, coll *collection.Collection[string, int]) bool {
+		called++
+		return called < 2
+	})
+	if called != 2 {
+		t.Errorf("Iteration should stop when function returns false, called %d times", called)
+	}
+}
+
+// TestCollectionTee tests the Tee method
+func TestCollectionTee(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+
+	var logged []string
+	sum := 0
+	result := c.Tee(
+		func(value int, key string) { logged = append(logged, key) },
+		func(value int, key string) { sum += value },
+	)
+	if result != c {
+		t.Error("Tee should return the collection for chaining")
+	}
+	if len(logged) != 3 {
+		t.Errorf("First consumer should see 3 entries, got %d", len(logged))
+	}
+	if sum != 6 {
+		t.Errorf("Second consumer should sum to 6, got %d", sum)
+	}
+
+	// Both consumers see each entry in the same order
+	var first, second []string
+	c.Tee(
+		func(value int, key string) { first = append(first, key) },
+		func(value int, key string) { second = append(second, key) },
+	)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Consumers should see the same entries in the same order, got %v and %v", first, second)
+	}
+}
+
 // TestCollectionTap tests the Tap method
 func TestCollectionTap(t *testing.T) {
 	c := collection.New[string, int]()
@@ -1607,6 +1806,33 @@ func TestCollectionFilter(t *testing.T) {
 	}
 }
 
+// TestCollectionCloneWhere tests the CloneWhere method
+func TestCollectionCloneWhere(t *testing.T) {
+	c := collection.New[string, int]()
+	even := func(value int, key string) bool { return value%2 == 0 }
+
+	if result := c.CloneWhere(even); result.Size() != 0 {
+		t.Errorf("CloneWhere on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+	result := c.CloneWhere(even)
+	if result.Size() != 2 || !result.HasAll("b", "d") {
+		t.Errorf("CloneWhere should keep only matching entries, got keys %v", result.Keys())
+	}
+	if result == c {
+		t.Error("CloneWhere should return a new collection")
+	}
+	if c.Size() != 4 {
+		t.Error("Original collection should be unchanged")
+	}
+
+	result.Set("e", 6)
+	if c.Has("e") {
+		t.Error("Modifying the clone should not affect the original")
+	}
+}
+
 // TestCollectionPartition tests the Partition method
 func TestCollectionPartition(t *testing.T) {
 	c := collection.New[string, int]()
@@ -1766,6 +1992,37 @@ func TestCollectionSweep(t *testing.T) {
 	}
 }
 
+// TestCollectionSweepConcurrentUpdate tests that Sweep keeps items replaced after the predicate saw them
+func TestCollectionSweepConcurrentUpdate(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	removed := c.Sweep(func(value int, key string, coll *collection.Collection[string, int]) bool {
+		if key == "a" {
+			coll.Set("a", 100) // a fresh value written after the snapshot
+		}
+		return true
+	})
+	if removed != 1 {
+		t.Errorf("Expected only the unchanged item to be removed, removed %d", removed)
+	}
+	if val, ok := c.Get("a"); !ok || val != 100 {
+		t.Errorf("Expected the fresh value 100 to survive, got %d (ok=%v)", val, ok)
+	}
+}
+
+// TestCollectionSweepNaN tests that Sweep removes values that never equal themselves
+func TestCollectionSweepNaN(t *testing.T) {
+	c := collection.New[string, float64]().Set("a", math.NaN()).Set("b", 1).Set("c", math.NaN())
+	removed := c.Sweep(func(value float64, key string, coll *collection.Collection[string, float64]) bool {
+		return math.IsNaN(value)
+	})
+	if removed != 2 {
+		t.Errorf("Expected 2 NaN items removed, removed %d", removed)
+	}
+	if c.Size() != 1 || !c.Has("b") {
+		t.Errorf("Expected only b to remain, got %v", c.Keys())
+	}
+}
+
 // TestCollectionSome tests the Some method
 func TestCollectionSome(t *testing.T) {
 	c := collection.New[string, int]()
@@ -1994,6 +2251,48 @@ func TestCollectionSort(t *testing.T) {
 	}
 }
 
+// TestCollectionTopN tests the TopN and BottomN methods
+func TestCollectionTopN(t *testing.T) {
+	byValue := func(firstValue, secondValue int, firstKey, secondKey string) int {
+		return firstValue - secondValue
+	}
+
+	c := collection.New[string, int]()
+	if c.TopN(3, byValue).Size() != 0 || c.BottomN(3, byValue).Size() != 0 {
+		t.Error("TopN and BottomN on empty collection should be empty")
+	}
+
+	c.Set("a", 5).Set("b", 1).Set("c", 9).Set("d", 3).Set("e", 7)
+
+	top := c.TopN(2, byValue)
+	if top.Size() != 2 || !top.HasAll("c", "e") {
+		t.Errorf("TopN(2) should return the two largest entries, got keys %v", top.Keys())
+	}
+
+	bottom := c.BottomN(2, byValue)
+	if bottom.Size() != 2 || !bottom.HasAll("b", "d") {
+		t.Errorf("BottomN(2) should return the two smallest entries, got keys %v", bottom.Keys())
+	}
+
+	if v, _ := top.Get("c"); v != 9 {
+		t.Errorf("TopN should preserve values, got %d", v)
+	}
+
+	// Saturates to the collection size
+	if c.TopN(10, byValue).Size() != 5 || c.BottomN(10, byValue).Size() != 5 {
+		t.Error("TopN and BottomN should saturate to the collection size")
+	}
+
+	// Non-positive n returns an empty collection
+	if c.TopN(0, byValue).Size() != 0 || c.BottomN(-1, byValue).Size() != 0 {
+		t.Error("TopN and BottomN with n <= 0 should be empty")
+	}
+
+	if c.Size() != 5 {
+		t.Error("Original collection should be unchanged")
+	}
+}
+
 // TestCollectionReverse tests the Reverse method
 func TestCollectionReverse(t *testing.T) {
 	c := collection.New[string, int]()
@@ -2681,6 +2980,106 @@ func TestCollectionConcat(t *testing.T) {
 	}
 }
 
+// TestCollectionCopyTo tests the CopyTo method
+func TestCollectionCopyTo(t *testing.T) {
+	source := collection.New[string, int]()
+	target := collection.New[string, int]()
+
+	if result := source.CopyTo(target); result != target || target.Size() != 0 {
+		t.Error("Copying an empty collection should leave target empty and return it")
+	}
+
+	source.Set("a", 1).Set("b", 2)
+	target.Set("b", 20).Set("c", 30)
+	source.CopyTo(target)
+
+	if target.Size() != 3 {
+		t.Errorf("Expected target size 3, got %d", target.Size())
+	}
+	if v, _ := target.Get("b"); v != 2 {
+		t.Errorf("Conflicting key should be overwritten with 2, got %d", v)
+	}
+	if source.Size() != 2 {
+		t.Error("Source collection should be unchanged")
+	}
+
+	// Copying to itself is a no-op
+	if source.CopyTo(source) != source || source.Size() != 2 {
+		t.Error("Copying a collection to itself should be a no-op")
+	}
+
+	// Concurrent copies in both directions must not deadlock
+	x := collection.New[int, int]()
+	y := collection.New[int, int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			x.Set(n, n)
+			x.CopyTo(y)
+		}(i)
+		go func(n int) {
+			defer wg.Done()
+			y.Set(-n, n)
+			y.CopyTo(x)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestCollectionMoveEntry tests the MoveEntry method
+func TestCollectionMoveEntry(t *testing.T) {
+	source := collection.New[string, int]()
+	target := collection.New[string, int]()
+	source.Set("a", 1).Set("b", 2)
+	target.Set("a", 100)
+
+	if !source.MoveEntry("a", target) {
+		t.Error("Moving an existing key should return true")
+	}
+	if source.Has("a") {
+		t.Error("Moved key should be removed from source")
+	}
+	if v, _ := target.Get("a"); v != 1 {
+		t.Errorf("Moved value should overwrite target, got %d", v)
+	}
+
+	if source.MoveEntry("missing", target) {
+		t.Error("Moving a missing key should return false")
+	}
+	if source.Size() != 1 || target.Size() != 1 {
+		t.Error("Moving a missing key should not change either collection")
+	}
+
+	if !source.MoveEntry("b", source) || !source.Has("b") {
+		t.Error("Moving to the same collection should keep the entry")
+	}
+
+	// Concurrent moves in both directions must not deadlock or lose entries
+	x := collection.New[int, int]()
+	y := collection.New[int, int]()
+	for i := 0; i < 100; i++ {
+		x.Set(i, i)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			x.MoveEntry(n, y)
+		}(i)
+		go func(n int) {
+			defer wg.Done()
+			y.MoveEntry(n, x)
+		}(i)
+	}
+	wg.Wait()
+	if x.Size()+y.Size() != 100 {
+		t.Errorf("Entries should never be lost or duplicated, got %d total", x.Size()+y.Size())
+	}
+}
+
 // TestCollectionEquals tests the Equals method
 func TestCollectionEquals(t *testing.T) {
 	c1 := collection.New[string, int]()
@@ -3448,3 +3847,1156 @@ func TestCollectionTypeEdgeCases(t *testing.T) {
 		t.Error("Boolean key collection should have 2 items")
 	}
 }
+
+// TestReadableCollection tests that Collection satisfies ReadableCollection
+func TestReadableCollection(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+
+	sumEven := func(r collection.ReadableCollection[string, int]) int {
+		total := 0
+		for _, value := range r.ValuesWhere(func(value int, key string) bool { return value%2 == 0 }) {
+			total += value
+		}
+		return total
+	}
+
+	if got := sumEven(c); got != 2 {
+		t.Errorf("Expected sum of even values 2, got %d", got)
+	}
+
+	var r collection.ReadableCollection[string, int] = c
+	if r.Size() != 3 {
+		t.Errorf("ReadableCollection should report size 3, got %d", r.Size())
+	}
+	if val, ok := r.Get("b"); !ok || val != 2 {
+		t.Errorf("ReadableCollection Get should return 2, got %d (ok=%v)", val, ok)
+	}
+	if !r.HasAll("a", "b", "c") {
+		t.Error("ReadableCollection should have all keys")
+	}
+	if entry, ok := r.FindEntry(func(e collection.Entry[string, int]) bool { return e.Value == 3 }); !ok || entry.Key != "c" {
+		t.Errorf("ReadableCollection FindEntry should find c, got %+v (ok=%v)", entry, ok)
+	}
+
+	// Returned collections are copies
+	r.CloneWhere(func(value int, key string) bool { return true }).Set("z", 26)
+	if c.Has("z") {
+		t.Error("Modifying a collection returned by ReadableCollection should not affect the original")
+	}
+}
+
+// TestCollectionIsSubset tests the IsSubset method
+func TestCollectionIsSubset(t *testing.T) {
+	c1 := collection.New[string, int]()
+	c2 := collection.New[string, any]()
+
+	// Empty collection is a subset of anything
+	if !c1.IsSubset(c2) {
+		t.Error("Empty collection should be a subset of an empty collection")
+	}
+	c2.Set("a", 1).Set("b", 2).Set("c", 3)
+	if !c1.IsSubset(c2) {
+		t.Error("Empty collection should be a subset of any collection")
+	}
+
+	c1.Set("a", 10).Set("b", 20)
+	if !c1.IsSubset(c2) {
+		t.Error("Collection should be a subset when all keys are present in other")
+	}
+
+	c1.Set("d", 40)
+	if c1.IsSubset(c2) {
+		t.Error("Collection should not be a subset when a key is missing from other")
+	}
+
+	// Concurrent checks in both directions alongside writers must not deadlock
+	x := collection.New[string, any]().Set("a", 1)
+	y := collection.New[string, any]().Set("a", 2)
+	if !x.IsSubset(x) || !x.IsSuperset(x) {
+		t.Error("A collection should be a subset and superset of itself")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			x.IsSubset(y)
+		}()
+		go func() {
+			defer wg.Done()
+			y.IsSuperset(x)
+		}()
+		go func(n int) {
+			defer wg.Done()
+			x.Set("b", n)
+		}(i)
+		go func(n int) {
+			defer wg.Done()
+			y.Set("b", n)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestCollectionIsSuperset tests the IsSuperset method
+func TestCollectionIsSuperset(t *testing.T) {
+	c1 := collection.New[string, int]()
+	c2 := collection.New[string, any]()
+
+	// Anything is a superset of an empty collection
+	if !c1.IsSuperset(c2) {
+		t.Error("Empty collection should be a superset of an empty collection")
+	}
+	c1.Set("a", 1).Set("b", 2).Set("c", 3)
+	if !c1.IsSuperset(c2) {
+		t.Error("Any collection should be a superset of an empty collection")
+	}
+
+	c2.Set("a", "x").Set("c", "z")
+	if !c1.IsSuperset(c2) {
+		t.Error("Collection should be a superset when it contains all keys of other")
+	}
+
+	c2.Set("d", "w")
+	if c1.IsSuperset(c2) {
+		t.Error("Collection should not be a superset when other has an extra key")
+	}
+}
+
+// TestCollectionIsDisjoint tests the IsDisjoint method
+func TestCollectionIsDisjoint(t *testing.T) {
+	c1 := collection.New[string, int]()
+	c2 := collection.New[string, any]()
+
+	if !c1.IsDisjoint(c2) {
+		t.Error("Two empty collections should be disjoint")
+	}
+
+	c1.Set("a", 1).Set("b", 2)
+	c2.Set("c", 3).Set("d", 4).Set("e", 5)
+	if !c1.IsDisjoint(c2) {
+		t.Error("Collections without shared keys should be disjoint")
+	}
+
+	c2.Set("b", "shared")
+	if c1.IsDisjoint(c2) {
+		t.Error("Collections sharing a key should not be disjoint")
+	}
+
+	// Scanning from the larger side gives the same answer
+	c1.Set("x", 10).Set("y", 20).Set("z", 30).Set("w", 40)
+	if c1.IsDisjoint(c2) {
+		t.Error("Collections sharing a key should not be disjoint regardless of size")
+	}
+
+	// Concurrent checks in both directions alongside a writer must not deadlock
+	x := collection.New[string, any]().Set("a", 1)
+	y := collection.New[string, any]().Set("b", 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			x.IsDisjoint(y)
+		}()
+		go func() {
+			defer wg.Done()
+			y.IsDisjoint(x)
+		}()
+		go func(n int) {
+			defer wg.Done()
+			x.Set("c", n)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestCollectionCompare tests the Compare method
+func TestCollectionCompare(t *testing.T) {
+	byValue := func(firstValue, secondValue int, firstKey, secondKey string) int {
+		switch {
+		case firstValue < secondValue:
+			return -1
+		case firstValue > secondValue:
+			return 1
+		}
+		return strings.Compare(firstKey, secondKey)
+	}
+
+	c1 := collection.New[string, int]()
+	c2 := collection.New[string, int]()
+
+	if c1.Compare(c2, byValue) != 0 {
+		t.Error("Two empty collections should compare equal")
+	}
+	if c1.Compare(c1, byValue) != 0 {
+		t.Error("A collection should compare equal to itself")
+	}
+
+	c1.Set("a", 1).Set("b", 2)
+	c2.Set("a", 1).Set("b", 2)
+	if c1.Compare(c2, byValue) != 0 {
+		t.Error("Equal collections should compare equal")
+	}
+
+	c2.Set("b", 3)
+	if c1.Compare(c2, byValue) != -1 || c2.Compare(c1, byValue) != 1 {
+		t.Error("Collection with smaller entry should sort first")
+	}
+
+	// A prefix sorts first
+	c2.Set("b", 2).Set("c", 3)
+	if c1.Compare(c2, byValue) != -1 || c2.Compare(c1, byValue) != 1 {
+		t.Error("Shorter collection should sort before a longer one with the same prefix")
+	}
+
+	// Comparator ties on unequal collections are still ordered
+	onlyValues := func(firstValue, secondValue int, firstKey, secondKey string) int {
+		return firstValue - secondValue
+	}
+	c3 := collection.New[string, int]().Set("x", 1)
+	c4 := collection.New[string, int]().Set("y", 1)
+	if c3.Compare(c4, onlyValues) == 0 {
+		t.Error("Compare should return 0 only for equal collections")
+	}
+	if c3.Compare(c4, onlyValues) != -c4.Compare(c3, onlyValues) {
+		t.Error("Compare should be antisymmetric")
+	}
+
+	// Ties in the comparator are broken the same way on every call, and 0 matches Equals
+	same := func(firstValue, secondValue int, firstKey, secondKey string) int { return 0 }
+	c5 := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+	c6 := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 4)
+	for i := 0; i < 50; i++ {
+		if r := c5.Compare(c5.Clone(), same); r != 0 {
+			t.Fatalf("Equal collections with comparator ties should compare 0, got %d", r)
+		}
+		if r := c5.Compare(c6, same); r != -1 {
+			t.Fatalf("Expected -1 on every call, got %d", r)
+		}
+		if r := c6.Compare(c5, same); r != 1 {
+			t.Fatalf("Expected 1 on every call, got %d", r)
+		}
+	}
+
+	// Usable for sorting slices of collections
+	colls := []*collection.Collection[string, int]{c2, c1, collection.New[string, int]()}
+	sort.Slice(colls, func(i, j int) bool {
+		return colls[i].Compare(colls[j], byValue) < 0
+	})
+	if colls[0].Size() != 0 || colls[1] != c1 || colls[2] != c2 {
+		t.Error("Collections should sort by Compare")
+	}
+
+	// Concurrent compares in both directions alongside a writer must not deadlock
+	x := collection.New[string, int]().Set("a", 1)
+	y := collection.New[string, int]().Set("a", 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			x.Compare(y, byValue)
+		}()
+		go func() {
+			defer wg.Done()
+			y.Compare(x, byValue)
+		}()
+		go func(n int) {
+			defer wg.Done()
+			x.Set("b", n)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestCollectionUniqueBy tests the UniqueBy method
+func TestCollectionUniqueBy(t *testing.T) {
+	type User struct {
+		Name string
+		Role string
+	}
+
+	c := collection.New[string, User]()
+	result := c.UniqueBy(func(value User, key string) any { return value.Role })
+	if result.Size() != 0 {
+		t.Errorf("UniqueBy on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("alice", User{"Alice", "admin"}).
+		Set("bob", User{"Bob", "user"}).
+		Set("carol", User{"Carol", "admin"}).
+		Set("dave", User{"Dave", "user"})
+
+	result = c.UniqueBy(func(value User, key string) any { return value.Role })
+	if result.Size() != 2 {
+		t.Errorf("Expected one entry per role, got size %d", result.Size())
+	}
+	roles := make(map[string]int)
+	result.Each(func(value User, key string, coll *collection.Collection[string, User]) {
+		roles[value.Role]++
+	})
+	if roles["admin"] != 1 || roles["user"] != 1 {
+		t.Errorf("Expected one admin and one user, got %v", roles)
+	}
+	if c.Size() != 4 {
+		t.Error("Original collection should be unchanged")
+	}
+}
+
+// TestCollectionCompact tests the Compact method
+func TestCollectionCompact(t *testing.T) {
+	c := collection.New[string, int]()
+	if c.Compact().Size() != 0 {
+		t.Error("Compact on empty collection should be empty")
+	}
+
+	c.Set("a", 0).Set("b", 1).Set("c", 0).Set("d", 2)
+	result := c.Compact()
+	if result.Size() != 2 || !result.HasAll("b", "d") {
+		t.Errorf("Compact should keep only non-zero values, got keys %v", result.Keys())
+	}
+	if c.Size() != 4 {
+		t.Error("Original collection should be unchanged")
+	}
+
+	// Strings
+	s := collection.New[string, string]().Set("a", "").Set("b", "x")
+	if result := s.Compact(); result.Size() != 1 || !result.Has("b") {
+		t.Errorf("Compact should remove empty strings, got keys %v", result.Keys())
+	}
+
+	// Pointers
+	n := 5
+	p := collection.New[string, *int]().Set("nil", nil).Set("ptr", &n)
+	if result := p.Compact(); result.Size() != 1 || !result.Has("ptr") {
+		t.Errorf("Compact should remove nil pointers, got keys %v", result.Keys())
+	}
+
+	// Interfaces
+	i := collection.New[string, any]().Set("nil", nil).Set("zero", 0).Set("value", "x")
+	if result := i.Compact(); result.Size() != 1 || !result.Has("value") {
+		t.Errorf("Compact should remove nil and zero interface values, got keys %v", result.Keys())
+	}
+}
+
+// TestCollectionReentrantCallbacks tests that callbacks may call methods on the same collection
+func TestCollectionReentrantCallbacks(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		c.Each(func(value int, key string, coll *collection.Collection[string, int]) {
+			coll.Set(key+"_copy", value)
+		})
+
+		filtered := c.Filter(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			return coll.Size() > 0 && !strings.HasSuffix(key, "_copy")
+		})
+		if filtered.Size() != 3 {
+			t.Errorf("Expected 3 original entries, got %d", filtered.Size())
+		}
+
+		removed := c.Sweep(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			return coll.Has(key) && strings.HasSuffix(key, "_copy")
+		})
+		if removed != 3 {
+			t.Errorf("Expected 3 removed copies, got %d", removed)
+		}
+
+		c.Find(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			coll.Delete("missing")
+			return false
+		})
+		c.Some(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			return coll.Has(key)
+		})
+		c.Every(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			return coll.Has(key)
+		})
+		c.Partition(func(value int, key string, coll *collection.Collection[string, int]) bool {
+			return coll.Size() > 1
+		})
+		collection.MapCollection(c, func(value int, key string, coll *collection.Collection[string, int]) int {
+			coll.Set(key, value)
+			return value
+		})
+		collection.ReduceCollection(c, func(acc int, value int, key string, coll *collection.Collection[string, int]) int {
+			return acc + coll.Size()
+		}, 0)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Calling collection methods from callbacks should not deadlock")
+	}
+
+	if c.Size() != 3 {
+		t.Errorf("Expected 3 entries after re-entrant operations, got %d", c.Size())
+	}
+}
+
+// TestCollectionTypedEntries tests the TypedEntries method
+func TestCollectionTypedEntries(t *testing.T) {
+	c := collection.New[string, int]()
+	if entries := c.TypedEntries(); len(entries) != 0 {
+		t.Errorf("Empty collection should have 0 entries, got %d", len(entries))
+	}
+
+	c.Set("key1", 10).Set("key2", 20)
+	entries := c.TypedEntries()
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(entries))
+	}
+
+	entryMap := make(map[string]int)
+	for _, entry := range entries {
+		entryMap[entry.Key] = entry.Value
+	}
+	if entryMap["key1"] != 10 || entryMap["key2"] != 20 {
+		t.Error("TypedEntries should contain correct key-value pairs")
+	}
+
+	// Entries round-trip through NewOf
+	if clone := collection.NewOf(entries...); clone.Size() != 2 || clone.Size() != c.Size() {
+		t.Errorf("Expected NewOf(TypedEntries()) to rebuild 2 items, got %d", clone.Size())
+	}
+}
+
+// TestCollectionSortedKeys tests the SortedKeys and SortedValues methods
+func TestCollectionSortedKeys(t *testing.T) {
+	c := collection.New[string, int]()
+	if keys := c.SortedKeys(strings.Compare); len(keys) != 0 {
+		t.Errorf("SortedKeys on empty collection should be empty, got %v", keys)
+	}
+
+	c.Set("b", 3).Set("c", 1).Set("a", 2)
+	keys := c.SortedKeys(strings.Compare)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Expected sorted keys [a b c], got %v", keys)
+	}
+
+	values := c.SortedValues(func(a, b int) int { return b - a })
+	if !reflect.DeepEqual(values, []int{3, 2, 1}) {
+		t.Errorf("Expected descending values [3 2 1], got %v", values)
+	}
+
+	// The collection itself is not modified
+	keys[0] = "z"
+	if c.Size() != 3 || c.Has("z") {
+		t.Error("SortedKeys should return a copy")
+	}
+}
+
+// TestCollectionIntersperse tests the Intersperse method
+func TestCollectionIntersperse(t *testing.T) {
+	c := collection.New[string, string]()
+	if result := collection.Intersperse(c, "sep", "-"); result.Size() != 0 {
+		t.Errorf("Intersperse on empty collection should be empty, got size %d", result.Size())
+	}
+
+	c.Set("a", "A")
+	if result := collection.Intersperse(c, "sep", "-"); result.Size() != 1 {
+		t.Errorf("Intersperse on a single item should add no separators, got size %d", result.Size())
+	}
+
+	// A single separator follows the same key rule as many
+	c.Set("b", "B")
+	result := collection.Intersperse(c, "sep", "-")
+	if val, ok := result.Get("sep_0"); !ok || val != "-" || result.Size() != 3 {
+		t.Errorf("Expected a single separator at sep_0, got %v", result.Keys())
+	}
+
+	c.Set("c", "C").Set("sep_0", "taken")
+	result = collection.Intersperse(c, "sep", "-")
+	if result.Size() != 7 {
+		t.Errorf("Expected 4 items and 3 separators, got size %d", result.Size())
+	}
+	if val, _ := result.Get("sep_0"); val != "taken" {
+		t.Errorf("Existing key sep_0 should be preserved, got %q", val)
+	}
+	for _, key := range []string{"sep_1", "sep_2", "sep_3"} {
+		if val, ok := result.Get(key); !ok || val != "-" {
+			t.Errorf("Expected separator at %q, got %q (ok=%v)", key, val, ok)
+		}
+	}
+	if c.Size() != 4 {
+		t.Error("Intersperse should not modify the original collection")
+	}
+
+	// Named string key types are accepted
+	type id string
+	n := collection.New[id, int]().Set("x", 1).Set("y", 2).Set("z", 3)
+	if result := collection.Intersperse(n, id("sep"), 0); result.Size() != 5 || !result.Has("sep_1") {
+		t.Errorf("Expected two separators for a named string key type, got %v", result.Keys())
+	}
+}
+
+// TestCollectionSplitAt tests the SplitAt method
+func TestCollectionSplitAt(t *testing.T) {
+	c := collection.New[string, int]()
+	head, tail := c.SplitAt(1)
+	if head.Size() != 0 || tail.Size() != 0 {
+		t.Error("SplitAt on empty collection should return two empty collections")
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+	tests := []struct {
+		index    int
+		headSize int
+	}{
+		{0, 0},
+		{1, 1},
+		{3, 3},
+		{-1, 3},
+		{-4, 0},
+		{10, 4},
+		{-10, 0},
+	}
+	for _, tt := range tests {
+		head, tail := c.SplitAt(tt.index)
+		if head.Size() != tt.headSize || tail.Size() != 4-tt.headSize {
+			t.Errorf("SplitAt(%d): expected sizes %d/%d, got %d/%d", tt.index, tt.headSize, 4-tt.headSize, head.Size(), tail.Size())
+		}
+		if union := head.Union(tail); union.Size() != 4 {
+			t.Errorf("SplitAt(%d): halves should cover every item, got %d", tt.index, union.Size())
+		}
+	}
+}
+
+// TestCollectionBatchEnsure tests the BatchEnsure method
+func TestCollectionBatchEnsure(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1)
+	var generated []string
+	generator := func(key string, coll *collection.Collection[string, int]) int {
+		generated = append(generated, key)
+		return len(key) * 10
+	}
+
+	result := c.BatchEnsure([]string{"a", "bb", "ccc", "bb"}, generator)
+	if result != c {
+		t.Error("BatchEnsure should return the collection for chaining")
+	}
+	if val, _ := c.Get("a"); val != 1 {
+		t.Errorf("Existing value should be kept, got %d", val)
+	}
+	if val, _ := c.Get("bb"); val != 20 {
+		t.Errorf("Expected generated 20, got %d", val)
+	}
+	if val, _ := c.Get("ccc"); val != 30 {
+		t.Errorf("Expected generated 30, got %d", val)
+	}
+	if !reflect.DeepEqual(generated, []string{"bb", "ccc"}) {
+		t.Errorf("Generator should only run for missing keys, got %v", generated)
+	}
+
+	generated = nil
+	c.BatchEnsure([]string{"a", "bb"}, generator)
+	if len(generated) != 0 {
+		t.Errorf("Generator should not run when all keys exist, got %v", generated)
+	}
+}
+
+// TestCollectionSetOrUpdate tests the SetOrUpdate method
+func TestCollectionSetOrUpdate(t *testing.T) {
+	c := collection.New[string, int]()
+	increment := func(existing int) int { return existing + 1 }
+
+	c.SetOrUpdate("hits", 1, increment)
+	if val, _ := c.Get("hits"); val != 1 {
+		t.Errorf("Expected initial value 1, got %d", val)
+	}
+	c.SetOrUpdate("hits", 1, increment).SetOrUpdate("hits", 1, increment)
+	if val, _ := c.Get("hits"); val != 3 {
+		t.Errorf("Expected updated value 3, got %d", val)
+	}
+
+	// Concurrent upserts do not lose updates
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.SetOrUpdate("concurrent", 1, increment)
+		}()
+	}
+	wg.Wait()
+	if val, _ := c.Get("concurrent"); val != 50 {
+		t.Errorf("Expected 50 after concurrent upserts, got %d", val)
+	}
+}
+
+// TestCollectionSetOrUpdateWritePath tests that SetOrUpdate stores values the way Set does
+func TestCollectionSetOrUpdateWritePath(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Use(func(op collection.CollectionOp, key string, value *int, next func()) {
+		if op == collection.OpSet {
+			*value *= 10
+		}
+		next()
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WaitUntil(context.Background(), func(c *collection.Collection[string, int]) bool {
+			return c.Has("a")
+		})
+	}()
+
+	c.SetOrUpdate("a", 1, func(existing int) int { return existing + 1 })
+	if val, _ := c.Get("a"); val != 10 {
+		t.Errorf("Expected middleware to store 10, got %d", val)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SetOrUpdate should wake WaitUntil")
+	}
+
+	c.SetOrUpdate("a", 1, func(existing int) int { return existing + 1 })
+	if val, _ := c.Get("a"); val != 110 {
+		t.Errorf("Expected ifPresent to see the stored value and middleware to apply again, got %d", val)
+	}
+}
+
+// TestCollectionMergeWith tests the MergeWith method
+func TestCollectionMergeWith(t *testing.T) {
+	c1 := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	c2 := collection.New[string, int]().Set("b", 10).Set("c", 3)
+	sum := func(existing, incoming int) int { return existing + incoming }
+
+	merged := c1.MergeWith(c2, sum)
+	if merged.Size() != 3 {
+		t.Errorf("Expected 3 items, got %d", merged.Size())
+	}
+	for key, want := range map[string]int{"a": 1, "b": 12, "c": 3} {
+		if val, _ := merged.Get(key); val != want {
+			t.Errorf("Expected %s=%d, got %d", key, want, val)
+		}
+	}
+	if val, _ := c1.Get("b"); val != 2 || c1.Size() != 2 {
+		t.Error("MergeWith should not modify the original collections")
+	}
+
+	// Resolve receives the values in order
+	keepIncoming := c1.MergeWith(c2, func(existing, incoming int) int { return incoming })
+	if val, _ := keepIncoming.Get("b"); val != 10 {
+		t.Errorf("Expected incoming value 10, got %d", val)
+	}
+
+	// Merging a collection with itself
+	doubled := c1.MergeWith(c1, sum)
+	if val, _ := doubled.Get("a"); val != 2 {
+		t.Errorf("Expected self-merge to double a, got %d", val)
+	}
+}
+
+// TestCollectionMergeInto tests the MergeInto method
+func TestCollectionMergeInto(t *testing.T) {
+	c1 := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	c2 := collection.New[string, int]().Set("b", 10).Set("c", 3)
+	sum := func(existing, incoming int) int { return existing + incoming }
+
+	if result := c1.MergeInto(c2, sum); result != c1 {
+		t.Error("MergeInto should return the receiver")
+	}
+	for key, want := range map[string]int{"a": 1, "b": 12, "c": 3} {
+		if val, _ := c1.Get(key); val != want {
+			t.Errorf("Expected %s=%d, got %d", key, want, val)
+		}
+	}
+	if c2.Size() != 2 {
+		t.Error("MergeInto should not modify the other collection")
+	}
+
+	c1.MergeInto(c1, sum)
+	if val, _ := c1.Get("a"); val != 2 {
+		t.Errorf("Expected self-merge to double a, got %d", val)
+	}
+
+	// Opposite concurrent merges do not deadlock
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c1.MergeInto(c2, sum)
+		}()
+		go func() {
+			defer wg.Done()
+			c2.MergeInto(c1, sum)
+		}()
+	}
+	wg.Wait()
+}
+
+// TestCollectionDeepClone tests the DeepClone method
+func TestCollectionDeepClone(t *testing.T) {
+	type Profile struct {
+		Name string
+		Tags []string
+	}
+	c := collection.New[string, *Profile]().Set("alice", &Profile{Name: "Alice", Tags: []string{"admin"}})
+
+	clone, err := c.DeepClone()
+	if err != nil {
+		t.Fatalf("DeepClone returned error: %v", err)
+	}
+	original, _ := c.Get("alice")
+	copied, _ := clone.Get("alice")
+	if copied == original {
+		t.Error("DeepClone should not share pointer values")
+	}
+	if copied.Name != "Alice" || !reflect.DeepEqual(copied.Tags, []string{"admin"}) {
+		t.Errorf("DeepClone should copy values, got %+v", copied)
+	}
+
+	copied.Tags[0] = "guest"
+	if original.Tags[0] != "admin" {
+		t.Error("Modifying the clone should not affect the original")
+	}
+
+	empty, err := collection.New[string, int]().DeepClone()
+	if err != nil || empty.Size() != 0 {
+		t.Errorf("DeepClone of empty collection failed: size %d, err %v", empty.Size(), err)
+	}
+
+	// Values gob cannot encode produce an error
+	if _, err := collection.New[string, func()]().Set("f", func() {}).DeepClone(); err == nil {
+		t.Error("DeepClone should fail for values that are not gob-serializable")
+	}
+}
+
+// TestCollectionValidate tests the Validate method
+func TestCollectionValidate(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	positive := func(key string, value int) error {
+		if value <= 0 {
+			return fmt.Errorf("%s: value %d is not positive", key, value)
+		}
+		return nil
+	}
+	shortKey := func(key string, value int) error {
+		if len(key) > 1 {
+			return fmt.Errorf("%s: key is too long", key)
+		}
+		return nil
+	}
+
+	if errs := c.Validate(positive, shortKey); errs != nil {
+		t.Errorf("Expected nil for valid items, got %v", errs)
+	}
+
+	c.Set("cc", -1)
+	errs := c.Validate(positive, shortKey)
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors for the invalid item, got %v", errs)
+	}
+
+	// Validators may call methods on the collection
+	if errs := c.Validate(func(key string, value int) error {
+		c.Has(key)
+		return nil
+	}); errs != nil {
+		t.Errorf("Expected nil, got %v", errs)
+	}
+}
+
+// TestCollectionFilterNot tests the FilterNot method
+func TestCollectionFilterNot(t *testing.T) {
+	c := collection.New[string, int]()
+
+	filtered := c.FilterNot(func(value int, key string, collection *collection.Collection[string, int]) bool {
+		return true
+	})
+	if filtered.Size() != 0 {
+		t.Errorf("FilterNot on empty collection should return empty collection, got size %d", filtered.Size())
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+	filtered = c.FilterNot(func(value int, key string, collection *collection.Collection[string, int]) bool {
+		return value%2 == 0
+	})
+	if filtered.Size() != 2 || !filtered.Has("a") || !filtered.Has("c") {
+		t.Errorf("Expected only odd values to remain, got keys %v", filtered.Keys())
+	}
+	if c.Size() != 4 {
+		t.Error("FilterNot should not modify the original collection")
+	}
+}
+
+// TestCollectionFirstValue tests the FirstValue and LastValue methods
+func TestCollectionFirstValue(t *testing.T) {
+	c := collection.New[string, int]()
+
+	if _, ok := c.FirstValue(); ok {
+		t.Error("FirstValue on an empty collection should return false")
+	}
+	if _, ok := c.LastValue(); ok {
+		t.Error("LastValue on an empty collection should return false")
+	}
+
+	c.Set("only", 42)
+	if v, ok := c.FirstValue(); !ok || v != 42 {
+		t.Errorf("Expected FirstValue to return 42, got %d, %v", v, ok)
+	}
+	if v, ok := c.LastValue(); !ok || v != 42 {
+		t.Errorf("Expected LastValue to return 42, got %d, %v", v, ok)
+	}
+
+	c.Set("a", 1).Set("b", 2)
+	for _, fn := range []func() (int, bool){c.FirstValue, c.LastValue} {
+		if v, ok := fn(); !ok || !c.Some(func(value int, key string, coll *collection.Collection[string, int]) bool { return value == v }) {
+			t.Errorf("Expected a value from the collection, got %d, %v", v, ok)
+		}
+	}
+}
+
+// TestCollectionSetRandSource tests the SetRandSource method and NewWithRand
+func TestCollectionSetRandSource(t *testing.T) {
+	fill := func(c *collection.Collection[int, int]) *collection.Collection[int, int] {
+		for i := 0; i < 50; i++ {
+			c.Set(i, i*10)
+		}
+		return c
+	}
+	a := fill(collection.NewWithRand[int, int](rand.New(rand.NewSource(7))))
+	b := fill(collection.New[int, int]().SetRandSource(rand.New(rand.NewSource(7))))
+
+	for i := 0; i < 5; i++ {
+		if va, vb := a.Random(), b.Random(); va != vb {
+			t.Fatalf("Expected the same random value from equally seeded sources, got %v and %v", va, vb)
+		}
+		if ka, kb := a.RandomKey(3), b.RandomKey(3); !reflect.DeepEqual(ka, kb) {
+			t.Fatalf("Expected the same random keys from equally seeded sources, got %v and %v", ka, kb)
+		}
+	}
+
+	a.SetRandSource(nil)
+	if v := a.Random(); v == nil {
+		t.Error("Random should still work after restoring the global source")
+	}
+}
+
+// TestCollectionChunkBy tests the ChunkBy method
+func TestCollectionChunkBy(t *testing.T) {
+	c := collection.New[int, string]()
+	if chunks := c.ChunkBy(func(current, prev string, currentKey, prevKey int) bool { return true }); len(chunks) != 0 {
+		t.Errorf("Expected no chunks for an empty collection, got %d", len(chunks))
+	}
+
+	// Runs of equal values, visited in key order
+	for i, status := range []string{"up", "up", "down", "down", "down", "up"} {
+		c.Set(i, status)
+	}
+	chunks := c.ChunkBy(func(current, prev string, currentKey, prevKey int) bool {
+		return current != prev
+	})
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	for i, want := range [][]int{{0, 1}, {2, 3, 4}, {5}} {
+		keys := chunks[i].Keys()
+		sort.Ints(keys)
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("Expected chunk %d to hold keys %v, got %v", i, want, keys)
+		}
+	}
+
+	// Gaps between consecutive keys
+	gaps := collection.New[int, string]().Set(1, "a").Set(2, "b").Set(10, "c").Set(11, "d").Set(30, "e")
+	chunks = gaps.ChunkBy(func(current, prev string, currentKey, prevKey int) bool {
+		return currentKey-prevKey > 1
+	})
+	if len(chunks) != 3 || chunks[0].Size() != 2 || chunks[1].Size() != 2 || chunks[2].Size() != 1 {
+		t.Errorf("Expected chunks of sizes 2, 2, 1, got %d chunks", len(chunks))
+	}
+}
+
+// TestCollectionForEachEntry tests the ForEachEntry method
+func TestCollectionForEachEntry(t *testing.T) {
+	c := collection.New[string, int]()
+
+	calls := 0
+	c.ForEachEntry(func(entry collection.Entry[string, int], coll *collection.Collection[string, int]) {
+		calls++
+	})
+	if calls != 0 {
+		t.Errorf("ForEachEntry on empty collection should not call fn, got %d calls", calls)
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+	var entries []collection.Entry[string, int]
+	result := c.ForEachEntry(func(entry collection.Entry[string, int], coll *collection.Collection[string, int]) {
+		if coll != c {
+			t.Error("ForEachEntry should pass the collection itself")
+		}
+		entries = append(entries, entry)
+	})
+	if result != c {
+		t.Error("ForEachEntry should return the collection for chaining")
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	want := []collection.Entry[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Expected %v, got %v", want, entries)
+	}
+}
+
+// TestCollectionEnsureValue tests the EnsureValue method
+func TestCollectionEnsureValue(t *testing.T) {
+	c := collection.New[string, int]()
+
+	if val := c.EnsureValue("a", 10); val != 10 {
+		t.Errorf("Expected the default value 10 for a missing key, got %d", val)
+	}
+	if val, ok := c.Get("a"); !ok || val != 10 {
+		t.Errorf("Expected the default value to be stored, got %d, %v", val, ok)
+	}
+	if val := c.EnsureValue("a", 20); val != 10 {
+		t.Errorf("Expected the existing value 10, got %d", val)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			results[n] = c.EnsureValue("shared", n)
+		}(i)
+	}
+	wg.Wait()
+	stored, _ := c.Get("shared")
+	for _, r := range results {
+		if r != stored {
+			t.Fatalf("Expected every caller to see the stored value %d, got %v", stored, results)
+		}
+	}
+}
+
+// TestCollectionForEachUntil tests the ForEachUntil method
+func TestCollectionForEachUntil(t *testing.T) {
+	c := collection.New[string, int]()
+	if err := c.ForEachUntil(func(value int, key string, coll *collection.Collection[string, int]) error {
+		return fmt.Errorf("should not be called")
+	}); err != nil {
+		t.Errorf("ForEachUntil on empty collection should return nil, got %v", err)
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+	visited := 0
+	if err := c.ForEachUntil(func(value int, key string, coll *collection.Collection[string, int]) error {
+		visited++
+		return nil
+	}); err != nil || visited != 3 {
+		t.Errorf("Expected all 3 items visited and a nil error, got %d visited and %v", visited, err)
+	}
+
+	errStop := fmt.Errorf("stop at %d", 2)
+	stopped := false
+	err := c.ForEachUntil(func(value int, key string, coll *collection.Collection[string, int]) error {
+		if stopped {
+			t.Error("fn should not be called after it returned an error")
+		}
+		if value == 2 {
+			stopped = true
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("Expected the error returned by fn, got %v", err)
+	}
+}
+
+// TestCollectionFill tests the Fill method
+func TestCollectionFill(t *testing.T) {
+	c := collection.New[string, int]()
+	if c.Fill(1).Size() != 0 {
+		t.Error("Fill should not add items to an empty collection")
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+	if result := c.Fill(0); result != c {
+		t.Error("Fill should return the collection for chaining")
+	}
+	if c.Size() != 3 {
+		t.Errorf("Fill should keep every key, got size %d", c.Size())
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		if val, _ := c.Get(key); val != 0 {
+			t.Errorf("Expected %s = 0 after Fill, got %d", key, val)
+		}
+	}
+}
+
+// TestCollectionReplaceAllWhere tests the ReplaceAllWhere method
+func TestCollectionReplaceAllWhere(t *testing.T) {
+	c := collection.New[string, string]().Set("a", "pending").Set("b", "done").Set("c", "running").Set("d", "pending")
+
+	n := c.ReplaceAllWhere(func(status string) bool {
+		return status == "pending" || status == "running"
+	}, "failed")
+	if n != 3 {
+		t.Errorf("Expected 3 replacements, got %d", n)
+	}
+	for key, want := range map[string]string{"a": "failed", "b": "done", "c": "failed", "d": "failed"} {
+		if val, _ := c.Get(key); val != want {
+			t.Errorf("Expected %s = %s, got %s", key, want, val)
+		}
+	}
+
+	if n := c.ReplaceAllWhere(func(string) bool { return false }, "x"); n != 0 {
+		t.Errorf("Expected no replacements, got %d", n)
+	}
+}
+
+// TestCollectionApplyToAll tests the ApplyToAll method
+func TestCollectionApplyToAll(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+
+	result := c.ApplyToAll(func(value int, key string) int {
+		if key == "c" {
+			return -value
+		}
+		return value * 10
+	})
+	if result != c {
+		t.Error("ApplyToAll should return the collection for chaining")
+	}
+	for key, want := range map[string]int{"a": 10, "b": 20, "c": -3} {
+		if val, _ := c.Get(key); val != want {
+			t.Errorf("Expected %s = %d, got %d", key, want, val)
+		}
+	}
+	if c.Size() != 3 {
+		t.Errorf("ApplyToAll should keep every key, got size %d", c.Size())
+	}
+}
+
+// TestCollectionFindEntry tests the FindEntry method
+func TestCollectionFindEntry(t *testing.T) {
+	c := collection.New[string, int]()
+	if _, ok := c.FindEntry(func(collection.Entry[string, int]) bool { return true }); ok {
+		t.Error("FindEntry on an empty collection should return false")
+	}
+
+	c.Set("a", 10).Set("b", 60).Set("c", 30)
+	entry, ok := c.FindEntry(func(e collection.Entry[string, int]) bool {
+		return e.Value > 50
+	})
+	if !ok || entry != (collection.Entry[string, int]{Key: "b", Value: 60}) {
+		t.Errorf("Expected entry b=60, got %+v, %v", entry, ok)
+	}
+	if _, ok := c.FindEntry(func(e collection.Entry[string, int]) bool { return e.Key == "z" }); ok {
+		t.Error("FindEntry should return false when nothing matches")
+	}
+
+	// fn may modify the collection without deadlocking
+	c.FindEntry(func(e collection.Entry[string, int]) bool {
+		c.Set(e.Key+"_seen", e.Value)
+		return false
+	})
+	if c.Size() != 6 {
+		t.Errorf("Expected 6 items after writes from fn, got %d", c.Size())
+	}
+}
+
+// TestCollectionAtEntry tests the AtEntry, FirstEntry and LastEntry methods
+func TestCollectionAtEntry(t *testing.T) {
+	c := collection.New[string, int]()
+	if _, ok := c.FirstEntry(); ok {
+		t.Error("FirstEntry on an empty collection should return false")
+	}
+	if _, ok := c.LastEntry(); ok {
+		t.Error("LastEntry on an empty collection should return false")
+	}
+	if _, ok := c.AtEntry(0); ok {
+		t.Error("AtEntry on an empty collection should return false")
+	}
+
+	c.Set("only", 7)
+	want := collection.Entry[string, int]{Key: "only", Value: 7}
+	for name, fn := range map[string]func() (collection.Entry[string, int], bool){
+		"FirstEntry":  c.FirstEntry,
+		"LastEntry":   c.LastEntry,
+		"AtEntry(0)":  func() (collection.Entry[string, int], bool) { return c.AtEntry(0) },
+		"AtEntry(-1)": func() (collection.Entry[string, int], bool) { return c.AtEntry(-1) },
+	} {
+		if entry, ok := fn(); !ok || entry != want {
+			t.Errorf("Expected %s to return %+v, got %+v, %v", name, want, entry, ok)
+		}
+	}
+
+	c.Set("a", 1).Set("b", 2)
+	for i := -3; i < 3; i++ {
+		entry, ok := c.AtEntry(i)
+		if val, _ := c.Get(entry.Key); !ok || val != entry.Value {
+			t.Errorf("Expected AtEntry(%d) to return a stored item, got %+v, %v", i, entry, ok)
+		}
+	}
+	if _, ok := c.AtEntry(3); ok {
+		t.Error("AtEntry should return false for an out-of-range index")
+	}
+	if _, ok := c.AtEntry(-4); ok {
+		t.Error("AtEntry should return false for an out-of-range negative index")
+	}
+}
+
+// TestCollectionRandomEntry tests the RandomEntry method
+func TestCollectionRandomEntry(t *testing.T) {
+	c := collection.New[string, int]()
+	if c.RandomEntry() != nil {
+		t.Error("RandomEntry on an empty collection should return nil")
+	}
+
+	c.Set("a", 1).Set("b", 2).Set("c", 3)
+	entry, ok := c.RandomEntry().(collection.Entry[string, int])
+	if !ok {
+		t.Fatalf("Expected an Entry, got %T", c.RandomEntry())
+	}
+	if val, _ := c.Get(entry.Key); val != entry.Value {
+		t.Errorf("Expected a stored item, got %+v", entry)
+	}
+
+	entries, ok := c.RandomEntry(2).([]collection.Entry[string, int])
+	if !ok || len(entries) != 2 || entries[0].Key == entries[1].Key {
+		t.Errorf("Expected 2 unique entries, got %v", c.RandomEntry(2))
+	}
+	if entries := c.RandomEntry(10).([]collection.Entry[string, int]); len(entries) != 3 {
+		t.Errorf("Expected every item when amount exceeds the size, got %d", len(entries))
+	}
+	if entries := c.RandomEntry(0).([]collection.Entry[string, int]); len(entries) != 0 {
+		t.Errorf("Expected no entries for amount 0, got %d", len(entries))
+	}
+
+	seeded := func() *collection.Collection[string, int] {
+		return collection.NewWithRand[string, int](rand.New(rand.NewSource(3))).Set("a", 1).Set("b", 2).Set("c", 3)
+	}
+	if a, b := seeded().RandomEntry(2), seeded().RandomEntry(2); !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected equal selections from equally seeded sources, got %v and %v", a, b)
+	}
+}
+
+// TestCollectionSetAllocs tests that a plain Set of an existing key does not allocate
+func TestCollectionSetAllocs(t *testing.T) {
+	c := collection.New[int, int]().Set(1, 1)
+	if allocs := testing.AllocsPerRun(100, func() { c.Set(1, 2) }); allocs != 0 {
+		t.Errorf("Expected Set to make no allocations, got %v", allocs)
+	}
+}
+
+// BenchmarkSet measures Set on a collection with no watchers, hooks, middleware or other features enabled
+func BenchmarkSet(b *testing.B) {
+	c := collection.New[int, int]()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Set(i&1023, i)
+	}
+}
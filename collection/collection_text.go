@@ -0,0 +1,144 @@
+package collection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// MarshalText implements encoding.TextMarshaler. Collections with string keys are encoded as a JSON object;
+// others as a JSON array of [key, value] pairs, as produced by ToJSON.
+func (c *Collection[K, V]) MarshalText() ([]byte, error) {
+	if !keyIsString[K]() {
+		return c.ToJSON()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return json.Marshal(c.items)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the contents of the collection.
+// Both the object and the pair-array layout are accepted.
+func (c *Collection[K, V]) UnmarshalText(text []byte) error {
+	entries, err := decodeText[K, V](bytes.NewReader(text))
+	if err != nil {
+		return err
+	}
+	c.write(func(b *writeBatch[K, V]) {
+		b.replace(entries)
+	})
+	return nil
+}
+
+// decodeText decodes a collection in either text layout from r, which must hold nothing after it, and returns
+// its entries. String-keyed entries are returned in document order, so that of keys the collection normalizes
+// to the same key, the last one wins.
+func decodeText[K comparable, V any](r io.Reader) ([]Entry[K, V], error) {
+	br := bufio.NewReader(r)
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil, errors.New("collection: cannot unmarshal empty input into a collection")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			first = b
+			break
+		}
+	}
+	if err := br.UnreadByte(); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry[K, V]
+	dec := json.NewDecoder(br)
+	switch {
+	case first == '{' && !keyIsString[K]():
+		var items map[K]V
+		if err := dec.Decode(&items); err != nil {
+			return nil, err
+		}
+		for k, v := range items {
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		}
+	case first == '{', first == '[':
+		err := decodeJSONItems(dec, func(k K, v V) {
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("collection: cannot unmarshal input starting with %q into a collection", first)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("collection: unexpected data after the collection")
+	}
+	return entries, nil
+}
+
+// MarshalJSON implements json.Marshaler with the same layout as MarshalText. Without it, encoding/json
+// would fall back to MarshalText and embed the collection as a quoted string.
+func (c *Collection[K, V]) MarshalJSON() ([]byte, error) {
+	return c.MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler with the same layouts as UnmarshalText.
+func (c *Collection[K, V]) UnmarshalJSON(data []byte) error {
+	return c.UnmarshalText(data)
+}
+
+// WriteTo implements io.WriterTo, writing the collection to w in the MarshalText layout.
+// Items are streamed to w one at a time as ExportTo does for "json", rather than encoded into a single buffer first.
+// It returns the number of bytes written.
+func (c *Collection[K, V]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := c.ExportTo(cw, "json")
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom, reading r until EOF and replacing the contents of the collection
+// as UnmarshalText does. Items are decoded as they are read rather than after buffering the whole input.
+// It returns the number of bytes read.
+func (c *Collection[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	entries, err := decodeText[K, V](cr)
+	if err != nil {
+		return cr.n, err
+	}
+	c.write(func(b *writeBatch[K, V]) {
+		b.replace(entries)
+	})
+	return cr.n, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
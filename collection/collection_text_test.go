@@ -0,0 +1,162 @@
+package collection_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionMarshalText tests text round-tripping
+func TestCollectionMarshalText(t *testing.T) {
+	c := collection.New[string, int]().Set("b", 2).Set("a", 1)
+	text, err := c.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText returned error: %v", err)
+	}
+	if string(text) != `{"a":1,"b":2}` {
+		t.Errorf("Expected a JSON object, got %s", text)
+	}
+
+	restored := collection.New[string, int]().Set("stale", 0)
+	if err := restored.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText returned error: %v", err)
+	}
+	if !restored.Equals(c) {
+		t.Errorf("Expected UnmarshalText to replace the contents, got keys %v", restored.Keys())
+	}
+
+	// Non-string keys use the ToJSON pair layout
+	n := collection.New[int, string]().Set(1, "one")
+	text, err = n.MarshalText()
+	if err != nil || string(text) != `[[1,"one"]]` {
+		t.Errorf("Expected pair array, got %s (err=%v)", text, err)
+	}
+	restoredN := collection.New[int, string]()
+	if err := restoredN.UnmarshalText(text); err != nil || !restoredN.Equals(n) {
+		t.Errorf("Expected pair array round trip, got keys %v (err=%v)", restoredN.Keys(), err)
+	}
+
+	if err := restoredN.UnmarshalText([]byte("not json")); err == nil {
+		t.Error("UnmarshalText should fail for invalid input")
+	}
+}
+
+// TestCollectionTextInterfaces tests use through flag and encoding/json
+func TestCollectionTextInterfaces(t *testing.T) {
+	limits := collection.New[string, int]()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(limits, "limits", collection.New[string, int](), "per-operation limits")
+	if err := fs.Parse([]string{"-limits", `{"read":100}`}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if val, _ := limits.Get("read"); val != 100 {
+		t.Errorf("Expected read limit 100 from flag, got %d", val)
+	}
+
+	type Config struct {
+		Limits *collection.Collection[string, int] `json:"limits"`
+	}
+	data, err := json.Marshal(Config{Limits: limits})
+	if err != nil || string(data) != `{"limits":{"read":100}}` {
+		t.Errorf("Expected embedded JSON object, got %s (err=%v)", data, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil || !cfg.Limits.Equals(limits) {
+		t.Errorf("Expected JSON round trip, got err %v", err)
+	}
+}
+
+// TestCollectionWriteTo tests the WriteTo and ReadFrom methods
+func TestCollectionWriteTo(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	var _ io.WriterTo = c
+	var _ io.ReaderFrom = c
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo should not fail, got %v", err)
+	}
+	if n != int64(buf.Len()) || buf.String() != `{"a":1,"b":2}` {
+		t.Errorf("Expected 13 bytes of {\"a\":1,\"b\":2}, got %d bytes of %s", n, buf.String())
+	}
+
+	restored := collection.New[string, int]().Set("stale", 0)
+	size := int64(buf.Len())
+	n, err = restored.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom should not fail, got %v", err)
+	}
+	if n != size {
+		t.Errorf("Expected %d bytes read, got %d", size, n)
+	}
+	if !restored.Equals(c) {
+		t.Errorf("Expected the read collection to replace the contents, got %v", restored.Keys())
+	}
+
+	pairs := collection.New[int, string]()
+	if _, err := pairs.ReadFrom(strings.NewReader(`[[1,"one"],[2,"two"]]`)); err != nil {
+		t.Fatalf("ReadFrom should accept the pair layout, got %v", err)
+	}
+	if val, _ := pairs.Get(2); val != "two" {
+		t.Errorf("Expected 2 = two, got %q", val)
+	}
+
+	if _, err := pairs.ReadFrom(strings.NewReader("not json")); err == nil {
+		t.Error("ReadFrom should fail on invalid input")
+	}
+	if _, err := pairs.ReadFrom(strings.NewReader(`[[1,"one"]] [[2,"two"]]`)); err == nil {
+		t.Error("ReadFrom should fail on data after the collection")
+	}
+
+	// Input arriving in small reads is decoded as it is read
+	input := ` {"3":"three","4":"four"}` + "\n"
+	n, err = pairs.ReadFrom(iotest.OneByteReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("ReadFrom should accept an object with numeric keys, got %v", err)
+	}
+	if n != int64(len(input)) || pairs.Size() != 2 {
+		t.Errorf("Expected %d bytes and 2 items, got %d bytes and %d items", len(input), n, pairs.Size())
+	}
+}
+
+// writeCounter counts the Write calls made to it.
+type writeCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+// TestCollectionWriteToStreams tests that WriteTo writes a large collection in pieces
+func TestCollectionWriteToStreams(t *testing.T) {
+	c := collection.New[int, int]()
+	for i := 0; i < 10000; i++ {
+		c.Set(i, i)
+	}
+	var w writeCounter
+	n, err := c.WriteTo(&w)
+	if err != nil {
+		t.Fatalf("WriteTo should not fail, got %v", err)
+	}
+	if w.writes < 2 {
+		t.Errorf("Expected the items to be streamed in several writes, got %d", w.writes)
+	}
+	if n != int64(w.Len()) {
+		t.Errorf("Expected %d bytes counted, got %d", w.Len(), n)
+	}
+	restored := collection.New[int, int]()
+	if _, err := restored.ReadFrom(&w.Buffer); err != nil || !restored.Equals(c) {
+		t.Errorf("Expected the written collection to read back, got %d items and %v", restored.Size(), err)
+	}
+}
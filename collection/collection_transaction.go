@@ -0,0 +1,144 @@
+package collection
+
+// AtomicApply runs fn as an all-or-nothing operation while holding the write lock.
+// fn receives a working copy of the collection that normalizes keys as the collection does, so it may call any
+// method on it. If fn returns nil, the items fn wrote to the copy are committed as if by Set and Delete, so middleware,
+// the changelog, hooks and watchers see each of them; items fn did not write are left alone. If fn returns an error or
+// panics, the collection is left unchanged (the panic is re-raised after the lock is released).
+func (c *Collection[K, V]) AtomicApply(fn func(c *Collection[K, V]) error) error {
+	var err error
+	c.write(func(b *writeBatch[K, V]) {
+		work := c.newDerived()
+		for k, v := range c.items {
+			work.items[k] = v
+		}
+		// Follow the writes fn makes, in order, so that only those are committed
+		var written []K
+		seen := make(map[K]struct{})
+		cleared := false
+		work.followers = map[uint64]func(change[K, V]){0: func(ch change[K, V]) {
+			if ch.op == EventClear {
+				cleared = true
+				return
+			}
+			if _, ok := seen[ch.key]; !ok {
+				seen[ch.key] = struct{}{}
+				written = append(written, ch.key)
+			}
+		}}
+		if err = fn(work); err != nil {
+			return
+		}
+		work.mu.RLock()
+		defer work.mu.RUnlock()
+		if cleared {
+			for _, k := range c.keysUnlocked() {
+				if _, ok := seen[k]; !ok {
+					seen[k] = struct{}{}
+					written = append(written, k)
+				}
+			}
+		}
+		for _, k := range written {
+			if _, ok := work.items[k]; !ok {
+				b.delete(k)
+			}
+		}
+		for _, k := range written {
+			if v, ok := work.items[k]; ok {
+				b.set(k, v)
+			}
+		}
+	})
+	return err
+}
+
+// LockedView reads a collection whose lock is held by WithReadLock or WithWriteLock. Its methods do not
+// acquire the lock, so the locked function can call them without deadlocking. Keys are normalized as the
+// collection normalizes them, but Get does not load missing values for a collection created by Memoize.
+// A LockedView must not be used after the locked function returns.
+type LockedView[K comparable, V any] struct {
+	c *Collection[K, V]
+}
+
+// Get retrieves an item from the collection.
+func (v *LockedView[K, V]) Get(key K) (V, bool) {
+	val, ok := v.c.items[v.c.normalizeKey(key)]
+	return val, ok
+}
+
+// Has checks if a key exists in the collection.
+func (v *LockedView[K, V]) Has(key K) bool {
+	_, ok := v.c.items[v.c.normalizeKey(key)]
+	return ok
+}
+
+// Size returns the number of items in the collection.
+func (v *LockedView[K, V]) Size() int {
+	return len(v.c.items)
+}
+
+// Keys returns all keys in the collection.
+func (v *LockedView[K, V]) Keys() []K {
+	return v.c.keysUnlocked()
+}
+
+// Values returns all values in the collection.
+func (v *LockedView[K, V]) Values() []V {
+	values := make([]V, 0, len(v.c.items))
+	for _, val := range v.c.items {
+		values = append(values, val)
+	}
+	return values
+}
+
+// TypedEntries returns all key-value pairs in the collection as typed entries.
+func (v *LockedView[K, V]) TypedEntries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(v.c.items))
+	for k, val := range v.c.items {
+		entries = append(entries, Entry[K, V]{Key: k, Value: val})
+	}
+	return entries
+}
+
+// LockedWriter is a LockedView that can also modify the collection, passed to the function run by WithWriteLock.
+// Its changes are made as Set, Delete and Clear make them, so middleware and the changelog apply to them;
+// hooks, watchers and waiters are notified once the lock is released.
+type LockedWriter[K comparable, V any] struct {
+	LockedView[K, V]
+	b *writeBatch[K, V]
+}
+
+// Set adds or updates an item in the collection.
+func (w *LockedWriter[K, V]) Set(key K, value V) *LockedWriter[K, V] {
+	w.b.set(key, value)
+	return w
+}
+
+// Delete removes an item from the collection.
+func (w *LockedWriter[K, V]) Delete(key K) bool {
+	_, removed := w.b.delete(key)
+	return removed
+}
+
+// Clear removes all items from the collection.
+func (w *LockedWriter[K, V]) Clear() *LockedWriter[K, V] {
+	w.b.clear()
+	return w
+}
+
+// WithWriteLock runs fn while holding the write lock, so that several operations are applied atomically.
+// fn receives a LockedWriter whose methods do not acquire the lock; changes take effect directly, with no rollback.
+func (c *Collection[K, V]) WithWriteLock(fn func(tx *LockedWriter[K, V])) {
+	c.write(func(b *writeBatch[K, V]) {
+		fn(&LockedWriter[K, V]{LockedView: LockedView[K, V]{c: c}, b: b})
+	})
+}
+
+// WithReadLock runs fn while holding the read lock, so that several reads observe the same state.
+// fn receives a LockedView whose methods do not acquire the lock.
+func (c *Collection[K, V]) WithReadLock(fn func(view *LockedView[K, V])) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fn(&LockedView[K, V]{c: c})
+}
@@ -0,0 +1,209 @@
+package collection_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionAtomicApply tests committing and rolling back changes
+func TestCollectionAtomicApply(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 1).Set("b", 2)
+
+	err := c.AtomicApply(func(tx *collection.Collection[string, int]) error {
+		tx.Set("c", 3)
+		tx.Delete("a")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Successful apply should return nil, got %v", err)
+	}
+	if c.Has("a") || !c.HasAll("b", "c") {
+		t.Errorf("Changes should be committed, got keys %v", c.Keys())
+	}
+
+	errFailed := errors.New("failed")
+	err = c.AtomicApply(func(tx *collection.Collection[string, int]) error {
+		tx.Set("d", 4)
+		tx.Clear()
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected the error returned by fn, got %v", err)
+	}
+	if c.Size() != 2 || !c.HasAll("b", "c") || c.Has("d") {
+		t.Errorf("Changes should be rolled back on error, got keys %v", c.Keys())
+	}
+}
+
+// TestCollectionAtomicApplyPanic tests rollback when fn panics
+func TestCollectionAtomicApplyPanic(t *testing.T) {
+	c := collection.New[string, int]()
+	c.Set("a", 1)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Panic should be re-raised, got %v", r)
+			}
+		}()
+		c.AtomicApply(func(tx *collection.Collection[string, int]) error {
+			tx.Set("b", 2)
+			panic("boom")
+		})
+	}()
+
+	if c.Size() != 1 || c.Has("b") {
+		t.Errorf("Changes should be rolled back on panic, got keys %v", c.Keys())
+	}
+	// The lock must have been released
+	c.Set("c", 3)
+}
+
+// TestCollectionAtomicApplyCommit tests that committed changes go through the collection's write path
+func TestCollectionAtomicApplyCommit(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2).EnableChangelog()
+
+	var sets, deletes []string
+	c.OnSet(func(key string, _ *int, _ int) { sets = append(sets, key) })
+	c.OnDelete(func(key string, _ int) { deletes = append(deletes, key) })
+	c.Use(func(op collection.CollectionOp, key string, value *int, next func()) {
+		if op == collection.OpSet {
+			*value *= 10
+		}
+		next()
+	})
+
+	err := c.AtomicApply(func(tx *collection.Collection[string, int]) error {
+		tx.Delete("a")
+		tx.Set("c", 3)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val, _ := c.Get("c"); val != 30 {
+		t.Errorf("Expected middleware to apply on commit, got c = %d", val)
+	}
+	if !reflect.DeepEqual(sets, []string{"c"}) || !reflect.DeepEqual(deletes, []string{"a"}) {
+		t.Errorf("Expected hooks for the changed items only, got sets %v and deletes %v", sets, deletes)
+	}
+	if n := len(c.Changelog()); n != 2 {
+		t.Errorf("Expected 2 changelog records, got %d", n)
+	}
+}
+
+// TestCollectionAtomicApplyWrittenOnly tests that only the items fn wrote are committed
+func TestCollectionAtomicApplyWrittenOnly(t *testing.T) {
+	c := collection.New[string, float64]().Set("nan", math.NaN()).Set("a", 1)
+	events, cancel := c.Watch()
+	defer cancel()
+
+	err := c.AtomicApply(func(tx *collection.Collection[string, float64]) error {
+		tx.Set("a", 2)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := len(events); n != 1 {
+		t.Errorf("Expected 1 event for the one written item, got %d", n)
+	}
+
+	// Clear writes every item that was present
+	err = c.AtomicApply(func(tx *collection.Collection[string, float64]) error {
+		tx.Clear()
+		tx.Set("b", 3)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Size() != 1 || !c.Has("b") {
+		t.Errorf("Expected only b after Clear and Set, got %v", c.Keys())
+	}
+}
+
+// TestCollectionWithWriteLock tests multi-step updates under the write lock
+func TestCollectionWithWriteLock(t *testing.T) {
+	c := collection.New[string, int]().Set("alice", 100).Set("bob", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.WithWriteLock(func(tx *collection.LockedWriter[string, int]) {
+				from, _ := tx.Get("alice")
+				to, _ := tx.Get("bob")
+				tx.Set("alice", from-1).Set("bob", to+1)
+			})
+		}()
+	}
+	wg.Wait()
+
+	alice, _ := c.Get("alice")
+	bob, _ := c.Get("bob")
+	if alice != 50 || bob != 50 {
+		t.Errorf("Expected 50/50 after concurrent transfers, got %d/%d", alice, bob)
+	}
+
+	// Operations that replace the items are kept too
+	c.WithWriteLock(func(tx *collection.LockedWriter[string, int]) {
+		tx.Clear().Set("carol", 1)
+	})
+	if c.Size() != 1 || !c.Has("carol") {
+		t.Errorf("Expected only carol after Clear and Set, got %v", c.Keys())
+	}
+}
+
+// TestCollectionWithWriteLockWritePath tests that writes through the locked view are observed like Set and Delete
+func TestCollectionWithWriteLockWritePath(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).EnableChangelog()
+	events, cancel := c.Watch()
+	defer cancel()
+
+	c.WithWriteLock(func(tx *collection.LockedWriter[string, int]) {
+		tx.Set("b", 2)
+		tx.Delete("a")
+		if tx.Size() != 1 || !tx.Has("b") {
+			t.Errorf("Expected the view to reflect its own writes, got keys %v", tx.Keys())
+		}
+	})
+
+	if n := len(c.Changelog()); n != 2 {
+		t.Errorf("Expected 2 changelog records, got %d", n)
+	}
+	for _, want := range []collection.EventType{collection.EventSet, collection.EventDelete} {
+		select {
+		case event := <-events:
+			if event.Type != want {
+				t.Errorf("Expected a %s event, got %s", want, event.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for a %s event", want)
+		}
+	}
+}
+
+// TestCollectionWithReadLock tests consistent reads under the read lock
+func TestCollectionWithReadLock(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+
+	var size, sum int
+	c.WithReadLock(func(view *collection.LockedView[string, int]) {
+		size = view.Size()
+		for _, v := range view.Values() {
+			sum += v
+		}
+	})
+	if size != 2 || sum != 3 {
+		t.Errorf("Expected size 2 and sum 3, got %d and %d", size, sum)
+	}
+}
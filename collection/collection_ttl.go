@@ -0,0 +1,201 @@
+package collection
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlEntry is a value stored in a TTLCollection along with its deadline.
+// A zero deadline means the entry never expires.
+type ttlEntry[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+// expired reports whether the entry has passed its deadline at the given time.
+func (e ttlEntry[V]) expired(now time.Time) bool {
+	return !e.deadline.IsZero() && !now.Before(e.deadline)
+}
+
+// TTLCollection is a map-like structure whose entries can expire after a duration.
+// Expired entries are removed lazily on access and, optionally, by a background sweeper started with StartGC.
+// It is safe for concurrent use.
+type TTLCollection[K comparable, V any] struct {
+	mu     sync.RWMutex
+	items  map[K]ttlEntry[V]
+	stopGC chan struct{}
+}
+
+// NewTTL creates a new TTLCollection.
+func NewTTL[K comparable, V any]() *TTLCollection[K, V] {
+	return &TTLCollection[K, V]{items: make(map[K]ttlEntry[V])}
+}
+
+// Set adds or updates an item that never expires.
+func (c *TTLCollection[K, V]) Set(key K, value V) *TTLCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = ttlEntry[V]{value: value}
+	return c
+}
+
+// SetWithTTL adds or updates an item that expires once ttl has elapsed.
+// A ttl <= 0 stores the item with no expiry.
+func (c *TTLCollection[K, V]) SetWithTTL(key K, value V, ttl time.Duration) *TTLCollection[K, V] {
+	entry := ttlEntry[V]{value: value}
+	if ttl > 0 {
+		entry.deadline = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = entry
+	return c
+}
+
+// Get retrieves a live item from the collection. Expired items are removed and reported as missing.
+func (c *TTLCollection[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if entry.expired(time.Now()) {
+		c.deleteIfExpired(key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Has checks if a live item exists for the key.
+func (c *TTLCollection[K, V]) Has(key K) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// TTL returns the remaining time to live for the key.
+// The second return value is false if the key is missing or expired; a zero duration means the item never expires.
+func (c *TTLCollection[K, V]) TTL(key K) (time.Duration, bool) {
+	c.mu.RLock()
+	entry, ok := c.items[key]
+	c.mu.RUnlock()
+	now := time.Now()
+	if !ok || entry.expired(now) {
+		return 0, false
+	}
+	if entry.deadline.IsZero() {
+		return 0, true
+	}
+	return entry.deadline.Sub(now), true
+}
+
+// Delete removes an item from the collection. Returns true if a live item was removed.
+func (c *TTLCollection[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, existed := c.items[key]
+	delete(c.items, key)
+	return existed && !entry.expired(time.Now())
+}
+
+// Clear removes all items from the collection.
+func (c *TTLCollection[K, V]) Clear() *TTLCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]ttlEntry[V])
+	return c
+}
+
+// Size returns the number of live items in the collection.
+func (c *TTLCollection[K, V]) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := time.Now()
+	count := 0
+	for _, entry := range c.items {
+		if !entry.expired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Keys returns the keys of all live items in the collection.
+func (c *TTLCollection[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := time.Now()
+	keys := make([]K, 0, len(c.items))
+	for k, entry := range c.items {
+		if !entry.expired(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// DeleteExpired removes all expired items. Returns the number of removed entries.
+func (c *TTLCollection[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	count := 0
+	for k, entry := range c.items {
+		if entry.expired(now) {
+			delete(c.items, k)
+			count++
+		}
+	}
+	return count
+}
+
+// StartGC starts a background goroutine that removes expired items every interval.
+// Calling StartGC again replaces the running sweeper. Use StopGC to stop it. An interval <= 0 is a no-op.
+func (c *TTLCollection[K, V]) StartGC(interval time.Duration) *TTLCollection[K, V] {
+	if interval <= 0 {
+		return c
+	}
+	stop := make(chan struct{})
+	c.mu.Lock()
+	if c.stopGC != nil {
+		close(c.stopGC)
+	}
+	c.stopGC = stop
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.DeleteExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// StopGC stops the background sweeper started by StartGC, if any.
+func (c *TTLCollection[K, V]) StopGC() *TTLCollection[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopGC != nil {
+		close(c.stopGC)
+		c.stopGC = nil
+	}
+	return c
+}
+
+// deleteIfExpired removes the item for key if it is still expired under the write lock.
+func (c *TTLCollection[K, V]) deleteIfExpired(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.items[key]; ok && entry.expired(time.Now()) {
+		delete(c.items, key)
+	}
+}
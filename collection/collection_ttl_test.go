@@ -0,0 +1,105 @@
+package collection_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestTTLCollectionSetAndGet tests storing items with and without expiry
+func TestTTLCollectionSetAndGet(t *testing.T) {
+	c := collection.NewTTL[string, int]()
+
+	c.Set("forever", 1)
+	c.SetWithTTL("short", 2, 20*time.Millisecond)
+
+	if val, ok := c.Get("forever"); !ok || val != 1 {
+		t.Errorf("Expected forever=1, got %d (ok=%v)", val, ok)
+	}
+	if val, ok := c.Get("short"); !ok || val != 2 {
+		t.Errorf("Expected short=2 before expiry, got %d (ok=%v)", val, ok)
+	}
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("Expired item should not be returned")
+	}
+	if c.Has("short") {
+		t.Error("Expired item should not be reported by Has")
+	}
+	if c.Size() != 1 {
+		t.Errorf("Size should count only live items, got %d", c.Size())
+	}
+	if keys := c.Keys(); len(keys) != 1 || keys[0] != "forever" {
+		t.Errorf("Expected only live keys [forever], got %v", keys)
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Error("Item without TTL should never expire")
+	}
+}
+
+// TestTTLCollectionTTL tests the TTL method
+func TestTTLCollectionTTL(t *testing.T) {
+	c := collection.NewTTL[string, int]()
+	c.Set("forever", 1)
+	c.SetWithTTL("timed", 2, time.Hour)
+
+	if d, ok := c.TTL("forever"); !ok || d != 0 {
+		t.Errorf("Item without TTL should report (0, true), got (%v, %v)", d, ok)
+	}
+	if d, ok := c.TTL("timed"); !ok || d <= 0 || d > time.Hour {
+		t.Errorf("Timed item should report remaining TTL, got (%v, %v)", d, ok)
+	}
+	if _, ok := c.TTL("missing"); ok {
+		t.Error("Missing item should report false")
+	}
+}
+
+// TestTTLCollectionDelete tests Delete and Clear
+func TestTTLCollectionDelete(t *testing.T) {
+	c := collection.NewTTL[string, int]()
+	c.Set("a", 1).SetWithTTL("b", 2, time.Millisecond)
+
+	if !c.Delete("a") {
+		t.Error("Deleting a live item should return true")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if c.Delete("b") {
+		t.Error("Deleting an expired item should return false")
+	}
+
+	c.Set("x", 1).Set("y", 2).Clear()
+	if c.Size() != 0 {
+		t.Errorf("Collection should be empty after Clear, got size %d", c.Size())
+	}
+}
+
+// TestTTLCollectionGC tests the background sweeper
+func TestTTLCollectionGC(t *testing.T) {
+	c := collection.NewTTL[string, int]()
+	c.SetWithTTL("a", 1, 5*time.Millisecond)
+	c.SetWithTTL("b", 2, 5*time.Millisecond)
+	c.Set("c", 3)
+
+	time.Sleep(10 * time.Millisecond)
+	if removed := c.DeleteExpired(); removed != 2 {
+		t.Errorf("DeleteExpired should remove 2 items, got %d", removed)
+	}
+
+	c.SetWithTTL("d", 4, 5*time.Millisecond)
+	c.StartGC(5 * time.Millisecond)
+	defer c.StopGC()
+
+	time.Sleep(50 * time.Millisecond)
+	if removed := c.DeleteExpired(); removed != 0 {
+		t.Errorf("Background sweeper should have removed expired items, %d left", removed)
+	}
+	if !c.Has("c") {
+		t.Error("Sweeper should not remove items without TTL")
+	}
+}
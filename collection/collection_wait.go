@@ -0,0 +1,59 @@
+package collection
+
+import (
+	"context"
+	"sync"
+)
+
+// WaitUntil blocks until fn returns true for the collection or ctx is done, in which case it returns ctx.Err().
+// fn is called immediately and again after every change to the collection, without any lock held, so it may call
+// any method on the collection.
+func (c *Collection[K, V]) WaitUntil(ctx context.Context, fn func(c *Collection[K, V]) bool) error {
+	c.waiters.Add(1)
+	defer c.waiters.Add(-1)
+
+	c.waitMu.Lock()
+	if c.waitCond == nil {
+		c.waitCond = sync.NewCond(&c.waitMu)
+	}
+	c.waitMu.Unlock()
+	stop := context.AfterFunc(ctx, func() {
+		c.waitMu.Lock()
+		defer c.waitMu.Unlock()
+		c.waitCond.Broadcast()
+	})
+	defer stop()
+
+	for {
+		c.waitMu.Lock()
+		gen := c.waitGen
+		c.waitMu.Unlock()
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if fn(c) {
+			return nil
+		}
+
+		c.waitMu.Lock()
+		for gen == c.waitGen && ctx.Err() == nil {
+			c.waitCond.Wait()
+		}
+		c.waitMu.Unlock()
+	}
+}
+
+// notifyWaiters wakes goroutines blocked in WaitUntil. It must be called after the change is visible,
+// with no lock held.
+func (c *Collection[K, V]) notifyWaiters() {
+	if c.waiters.Load() == 0 {
+		return
+	}
+	c.waitMu.Lock()
+	defer c.waitMu.Unlock()
+	c.waitGen++
+	if c.waitCond != nil {
+		c.waitCond.Broadcast()
+	}
+}
@@ -0,0 +1,83 @@
+package collection_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionWaitUntil tests waiting for a condition to become true
+func TestCollectionWaitUntil(t *testing.T) {
+	c := collection.New[int, int]()
+	atLeast := func(n int) func(c *collection.Collection[int, int]) bool {
+		return func(c *collection.Collection[int, int]) bool { return c.Size() >= n }
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WaitUntil(context.Background(), atLeast(3))
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-done:
+			t.Fatalf("WaitUntil returned early with %d items: %v", i, err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		c.Set(i, i)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitUntil did not return after the condition was met")
+	}
+
+	// A condition that already holds returns immediately
+	if err := c.WaitUntil(context.Background(), atLeast(1)); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+// TestCollectionWaitUntilCancel tests that cancellation stops the wait
+func TestCollectionWaitUntilCancel(t *testing.T) {
+	c := collection.New[string, int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.WaitUntil(ctx, func(c *collection.Collection[string, int]) bool {
+		return c.Has("never")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestCollectionWaitUntilDelete tests waking on Delete and Clear
+func TestCollectionWaitUntilDelete(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1).Set("b", 2)
+	empty := func(c *collection.Collection[string, int]) bool { return c.Size() == 0 }
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WaitUntil(context.Background(), empty)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	c.Delete("a")
+	c.Clear()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitUntil did not return after Clear")
+	}
+}
@@ -0,0 +1,96 @@
+package collection
+
+import "time"
+
+// watchBufferSize is the capacity of the channels returned by Watch.
+const watchBufferSize = 64
+
+// EventType identifies the kind of mutation that produced a CollectionEvent.
+type EventType int
+
+const (
+	// EventSet is produced when a value is stored, by Set or any other method.
+	EventSet EventType = iota
+	// EventDelete is produced when an item is removed, by Delete or any other method other than Clear.
+	EventDelete
+	// EventClear is produced by Clear.
+	EventClear
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// CollectionEvent describes a mutation of a collection.
+// For EventSet, OldValue is the zero value if the key was absent. For EventClear, Key, OldValue and NewValue are zero values.
+type CollectionEvent[K comparable, V any] struct {
+	Type      EventType
+	Key       K
+	OldValue  V
+	NewValue  V
+	Timestamp time.Time
+}
+
+// Watch registers a listener and returns a buffered channel that receives an event for every change to the collection:
+// an EventSet for each value stored and an EventDelete for each item removed, by any method, and an EventClear for each Clear.
+// Events are sent after the lock is released and are dropped if the channel's buffer is full, so watchers never block
+// mutations; delivery is best-effort, and DroppedEvents counts the events lost this way.
+// The returned cancel function deregisters the listener and closes the channel.
+func (c *Collection[K, V]) Watch() (<-chan CollectionEvent[K, V], func()) {
+	ch := make(chan CollectionEvent[K, V], watchBufferSize)
+	c.watchMu.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[chan CollectionEvent[K, V]]struct{})
+	}
+	c.watchers[ch] = struct{}{}
+	c.listeners.Add(1)
+	c.watchMu.Unlock()
+
+	cancel := func() {
+		c.watchMu.Lock()
+		defer c.watchMu.Unlock()
+		if _, ok := c.watchers[ch]; ok {
+			delete(c.watchers, ch)
+			c.listeners.Add(-1)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// emit sends an event to all watchers without blocking. It must be called without holding c.mu.
+func (c *Collection[K, V]) emit(eventType EventType, key K, oldValue, newValue V) {
+	c.watchMu.RLock()
+	defer c.watchMu.RUnlock()
+	if len(c.watchers) == 0 {
+		return
+	}
+	event := CollectionEvent[K, V]{
+		Type:      eventType,
+		Key:       key,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Timestamp: time.Now(),
+	}
+	for ch := range c.watchers {
+		select {
+		case ch <- event:
+		default:
+			c.droppedEvents.Add(1)
+		}
+	}
+}
+
+// DroppedEvents returns the number of events that could not be delivered to a watcher because its buffer was full.
+func (c *Collection[K, V]) DroppedEvents() uint64 {
+	return c.droppedEvents.Load()
+}
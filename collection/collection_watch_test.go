@@ -0,0 +1,172 @@
+package collection_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// receiveEvent waits briefly for an event on ch
+func receiveEvent[K comparable, V any](t *testing.T, ch <-chan collection.CollectionEvent[K, V]) collection.CollectionEvent[K, V] {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	return collection.CollectionEvent[K, V]{}
+}
+
+// TestCollectionWatch tests events produced by Set, Delete, and Clear
+func TestCollectionWatch(t *testing.T) {
+	c := collection.New[string, int]()
+	events, cancel := c.Watch()
+	defer cancel()
+
+	c.Set("a", 1)
+	event := receiveEvent(t, events)
+	if event.Type != collection.EventSet || event.Key != "a" || event.OldValue != 0 || event.NewValue != 1 {
+		t.Errorf("Unexpected set event %+v", event)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("Event should carry a timestamp")
+	}
+
+	c.Set("a", 2)
+	event = receiveEvent(t, events)
+	if event.OldValue != 1 || event.NewValue != 2 {
+		t.Errorf("Update event should carry old and new values, got %+v", event)
+	}
+
+	c.Delete("a")
+	event = receiveEvent(t, events)
+	if event.Type != collection.EventDelete || event.Key != "a" || event.OldValue != 2 {
+		t.Errorf("Unexpected delete event %+v", event)
+	}
+
+	c.Delete("missing")
+	c.Clear()
+	event = receiveEvent(t, events)
+	if event.Type != collection.EventClear {
+		t.Errorf("Deleting a missing key should not produce an event; expected clear, got %v", event.Type)
+	}
+	if event.Type.String() != "clear" {
+		t.Errorf("Expected event type name clear, got %s", event.Type)
+	}
+}
+
+// TestCollectionWatchCancel tests deregistering watchers
+func TestCollectionWatchCancel(t *testing.T) {
+	c := collection.New[string, int]()
+	first, cancelFirst := c.Watch()
+	second, cancelSecond := c.Watch()
+	defer cancelSecond()
+
+	cancelFirst()
+	cancelFirst() // canceling twice is safe
+	if _, ok := <-first; ok {
+		t.Error("Canceled watcher channel should be closed")
+	}
+
+	c.Set("a", 1)
+	if event := receiveEvent(t, second); event.Key != "a" {
+		t.Errorf("Remaining watcher should still receive events, got %+v", event)
+	}
+}
+
+// TestCollectionWatchDoesNotBlock tests that a full watcher does not block mutations
+func TestCollectionWatchDoesNotBlock(t *testing.T) {
+	c := collection.New[int, int]()
+	_, cancel := c.Watch()
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			c.Set(i, i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Mutations should not block on a slow watcher")
+	}
+	if dropped := c.DroppedEvents(); dropped != 1000-64 {
+		t.Errorf("Expected %d dropped events, got %d", 1000-64, dropped)
+	}
+}
+
+// TestCollectionWatchAllMutators tests that methods other than Set, Delete, and Clear produce events
+func TestCollectionWatchAllMutators(t *testing.T) {
+	c := collection.New[string, int]().Set("a", 1)
+	events, cancel := c.Watch()
+	defer cancel()
+
+	expect := func(desc string, typ collection.EventType, key string, newValue int) {
+		t.Helper()
+		event := receiveEvent(t, events)
+		if event.Type != typ || event.Key != key || event.NewValue != newValue {
+			t.Errorf("%s: expected %s %s=%d, got %+v", desc, typ, key, newValue, event)
+		}
+	}
+
+	c.SetDefault("b", 2)
+	expect("SetDefault", collection.EventSet, "b", 2)
+	c.Ensure("c", func(string, *collection.Collection[string, int]) int { return 3 })
+	expect("Ensure", collection.EventSet, "c", 3)
+	c.Sweep(func(value int, _ string, _ *collection.Collection[string, int]) bool { return value == 3 })
+	expect("Sweep", collection.EventDelete, "c", 0)
+	c.MergeInto(collection.New[string, int]().Set("d", 4), func(existing, incoming int) int { return incoming })
+	expect("MergeInto", collection.EventSet, "d", 4)
+	c.MoveEntry("d", collection.New[string, int]())
+	expect("MoveEntry", collection.EventDelete, "d", 0)
+	collection.Patch(c, collection.Diff(c, collection.New[string, int]().Set("a", 1).Set("b", 5)))
+	expect("Patch", collection.EventSet, "b", 5)
+	c.ApplyToAll(func(value int, key string) int { return value * 10 })
+	for i := 0; i < 2; i++ {
+		if event := receiveEvent(t, events); event.Type != collection.EventSet || event.NewValue%10 != 0 {
+			t.Errorf("ApplyToAll: expected a set event with the new value, got %+v", event)
+		}
+	}
+
+	target := collection.New[string, int]()
+	targetEvents, cancelTarget := target.Watch()
+	defer cancelTarget()
+	c.CopyTo(target)
+	for i := 0; i < 2; i++ {
+		if event := receiveEvent(t, targetEvents); event.Type != collection.EventSet {
+			t.Errorf("CopyTo: expected a set event on the target, got %+v", event)
+		}
+	}
+}
+
+// TestCollectionWatchConcurrent tests watching and canceling concurrently with mutations
+func TestCollectionWatchConcurrent(t *testing.T) {
+	c := collection.New[int, int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			events, cancel := c.Watch()
+			go func() {
+				for range events {
+				}
+			}()
+			time.Sleep(time.Millisecond)
+			cancel()
+		}()
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Set(n*100+j, j)
+				c.Delete(n*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,250 @@
+package collection
+
+// change is one mutation applied by a writeBatch, kept until it is published after the write lock is released.
+type change[K comparable, V any] struct {
+	op       EventType
+	key      K
+	oldValue V
+	existed  bool
+	newValue V
+	// cleared is the number of items removed by a Clear.
+	cleared int
+}
+
+// writeBatch applies the mutations of one write operation while the write lock is held.
+// Every method that modifies a collection goes through a writeBatch, so key normalization, middleware,
+// the changelog and the history apply to all of them, and watchers, hooks and waiters are told about
+// every change once the lock is released.
+//
+// Changes are only kept for publish while the collection has watchers or hooks, so a plain write on a
+// collection without them allocates nothing beyond the map entry. Callers on hot paths declare the batch
+// as a local variable rather than calling write, and its methods must not let the batch escape.
+type writeBatch[K comparable, V any] struct {
+	c       *Collection[K, V]
+	changes []change[K, V]
+	// changed is set by any change, whether or not it is kept, so that publish wakes waiters.
+	changed bool
+	// reverting is set by Revert, whose changes undo changelog records instead of adding new ones.
+	reverting bool
+}
+
+// write runs fn with a writeBatch for c while holding the write lock, then publishes the changes fn made.
+// If fn panics, the lock is released and nothing is published.
+func (c *Collection[K, V]) write(fn func(b *writeBatch[K, V])) {
+	b := &writeBatch[K, V]{c: c}
+	c.mu.Lock()
+	func() {
+		defer c.mu.Unlock()
+		fn(b)
+	}()
+	b.publish()
+}
+
+// writePair is like write for two distinct collections, locked as lockPair does. writeA and writeB select
+// the write lock; fn must not use the batch of a collection that is only read-locked to modify it.
+func writePair[K comparable, V any](a *Collection[K, V], writeA bool, b *Collection[K, V], writeB bool, fn func(ba, bb *writeBatch[K, V])) {
+	ba, bb := &writeBatch[K, V]{c: a}, &writeBatch[K, V]{c: b}
+	func() {
+		unlock := lockPair(a, writeA, b, writeB)
+		defer unlock()
+		fn(ba, bb)
+	}()
+	ba.publish()
+	bb.publish()
+}
+
+// get returns the value stored for key.
+func (b *writeBatch[K, V]) get(key K) (V, bool) {
+	val, ok := b.c.items[b.c.normalizeKey(key)]
+	return val, ok
+}
+
+// set stores value for key through the middleware and reports whether key existed beforehand.
+// Nothing is stored if a middleware blocks the operation.
+func (b *writeBatch[K, V]) set(key K, value V) bool {
+	c := b.c
+	key = c.normalizeKey(key)
+	_, existed := c.items[key]
+	if len(c.middleware) == 0 {
+		b.store(key, value)
+	} else {
+		b.setThrough(key, value)
+	}
+	return existed
+}
+
+// setThrough stores value for the normalized key through the middleware. The closure passed to the chain
+// escapes, so it captures the collection and the changes it makes rather than the batch.
+func (b *writeBatch[K, V]) setThrough(key K, value V) {
+	c, reverting := b.c, b.reverting
+	var made []change[K, V]
+	runMiddleware(c.middleware, OpSet, key, &value, func() {
+		made = append(made, c.storeItem(key, value, reverting))
+	})
+	for _, ch := range made {
+		b.record(ch)
+	}
+}
+
+// setDefault stores value for key through the middleware if key is absent, and returns the value stored
+// for key afterwards, or value if a middleware blocked the operation.
+func (b *writeBatch[K, V]) setDefault(key K, value V) V {
+	if existing, ok := b.get(key); ok {
+		return existing
+	}
+	b.set(key, value)
+	if stored, ok := b.get(key); ok {
+		return stored
+	}
+	return value
+}
+
+// delete removes key through the middleware and returns the removed value.
+// It returns false if key was absent or a middleware blocked the operation.
+func (b *writeBatch[K, V]) delete(key K) (V, bool) {
+	c := b.c
+	key = c.normalizeKey(key)
+	if len(c.middleware) == 0 {
+		return b.remove(key)
+	}
+	reverting := b.reverting
+	var old V
+	var made []change[K, V]
+	runMiddleware(c.middleware, OpDelete, key, nil, func() {
+		if ch, ok := c.removeItem(key, reverting); ok {
+			old = ch.oldValue
+			made = append(made, ch)
+		}
+	})
+	for _, ch := range made {
+		b.record(ch)
+	}
+	return old, len(made) > 0
+}
+
+// clear removes every item through the middleware.
+func (b *writeBatch[K, V]) clear() {
+	c := b.c
+	if len(c.middleware) == 0 {
+		b.removeAll()
+		return
+	}
+	reverting := b.reverting
+	var zeroKey K
+	var made []change[K, V]
+	runMiddleware(c.middleware, OpClear, zeroKey, nil, func() {
+		made = append(made, c.removeAllItems(reverting))
+	})
+	for _, ch := range made {
+		b.record(ch)
+	}
+}
+
+// replace clears the collection and stores entries in its place, in order, through the middleware.
+func (b *writeBatch[K, V]) replace(entries []Entry[K, V]) {
+	b.clear()
+	for _, e := range entries {
+		b.set(e.Key, e.Value)
+	}
+}
+
+// store sets the normalized key to value without running middleware.
+func (b *writeBatch[K, V]) store(key K, value V) {
+	b.record(b.c.storeItem(key, value, b.reverting))
+}
+
+// remove deletes the normalized key without running middleware and returns the removed value, if any.
+func (b *writeBatch[K, V]) remove(key K) (V, bool) {
+	ch, ok := b.c.removeItem(key, b.reverting)
+	if ok {
+		b.record(ch)
+	}
+	return ch.oldValue, ok
+}
+
+// removeAll deletes every item without running middleware.
+func (b *writeBatch[K, V]) removeAll() {
+	b.record(b.c.removeAllItems(b.reverting))
+}
+
+// record passes ch to the followers of the collection and keeps it for publish if anyone listens for it.
+func (b *writeBatch[K, V]) record(ch change[K, V]) {
+	b.changed = true
+	c := b.c
+	for _, follow := range c.followers {
+		follow(ch)
+	}
+	if c.listeners.Load() > 0 {
+		b.changes = append(b.changes, ch)
+	}
+}
+
+// storeItem sets the normalized key to value, records it in the changelog and history, and returns the change.
+// The caller must hold the write lock.
+func (c *Collection[K, V]) storeItem(key K, value V, reverting bool) change[K, V] {
+	old, existed := c.items[key]
+	c.items[key] = value
+	c.writeGen++
+	if c.changelogEnabled && !reverting {
+		// A copy scoped to this branch, so that value itself does not escape
+		newValue := value
+		c.recordChangeUnlocked(EventSet, key, valuePtr(old, existed), &newValue)
+	}
+	if c.historyEnabled {
+		c.recordHistoryUnlocked(key, value)
+	}
+	return change[K, V]{op: EventSet, key: key, oldValue: old, existed: existed, newValue: value}
+}
+
+// removeItem deletes the normalized key, records it in the changelog, and returns the change.
+// It returns false if key was absent. The caller must hold the write lock.
+func (c *Collection[K, V]) removeItem(key K, reverting bool) (change[K, V], bool) {
+	old, existed := c.items[key]
+	if !existed {
+		return change[K, V]{}, false
+	}
+	delete(c.items, key)
+	c.writeGen++
+	if c.changelogEnabled && !reverting {
+		oldValue := old
+		c.recordChangeUnlocked(EventDelete, key, &oldValue, nil)
+	}
+	return change[K, V]{op: EventDelete, key: key, oldValue: old, existed: true}, true
+}
+
+// removeAllItems deletes every item, records it in the changelog, and returns the change.
+// The caller must hold the write lock.
+func (c *Collection[K, V]) removeAllItems(reverting bool) change[K, V] {
+	var zeroKey K
+	cleared := c.items
+	c.items = make(map[K]V)
+	c.writeGen++
+	if c.changelogEnabled && !reverting {
+		c.recordChangeUnlocked(EventClear, zeroKey, nil, nil)
+		c.changelog[len(c.changelog)-1].cleared = cleared
+	}
+	return change[K, V]{op: EventClear, cleared: len(cleared)}
+}
+
+// publish tells watchers, hooks and waiters about the changes in the batch. It must be called without holding the lock.
+func (b *writeBatch[K, V]) publish() {
+	if !b.changed {
+		return
+	}
+	c := b.c
+	var zero V
+	for _, ch := range b.changes {
+		switch ch.op {
+		case EventSet:
+			c.emit(EventSet, ch.key, ch.oldValue, ch.newValue)
+			c.runSetHooks(ch.key, valuePtr(ch.oldValue, ch.existed), ch.newValue)
+		case EventDelete:
+			c.emit(EventDelete, ch.key, ch.oldValue, zero)
+			c.runDeleteHooks(ch.key, ch.oldValue)
+		case EventClear:
+			c.emit(EventClear, ch.key, zero, zero)
+			c.runClearHooks(ch.cleared)
+		}
+	}
+	c.notifyWaiters()
+}
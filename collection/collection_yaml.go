@@ -0,0 +1,85 @@
+package collection
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlEntry is the YAML form of an item of a collection whose keys are not strings.
+type yamlEntry[K comparable, V any] struct {
+	Key   K `yaml:"key"`
+	Value V `yaml:"value"`
+}
+
+// ToYAML returns the collection as YAML. Collections with string keys are encoded as a mapping;
+// others as a sequence of {key, value} mappings.
+func (c *Collection[K, V]) ToYAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// NewFromYAML creates a new Collection from YAML produced by ToYAML.
+func NewFromYAML[K comparable, V any](data []byte) (*Collection[K, V], error) {
+	c := New[K, V]()
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// MarshalYAML implements yaml.Marshaler, using the same layout as ToYAML.
+func (c *Collection[K, V]) MarshalYAML() (any, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if keyIsString[K]() {
+		items := make(map[K]V, len(c.items))
+		for k, v := range c.items {
+			items[k] = v
+		}
+		return items, nil
+	}
+	entries := make([]yamlEntry[K, V], 0, len(c.items))
+	for _, k := range c.sortedKeysUnlocked(func(_, _ V, a, b K) int { return compareNatural(a, b) }) {
+		entries = append(entries, yamlEntry[K, V]{Key: k, Value: c.items[k]})
+	}
+	return entries, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the contents of the collection.
+// Both the mapping and the sequence layout are accepted.
+func (c *Collection[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	var entries []Entry[K, V]
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var k K
+			var v V
+			if err := node.Content[i].Decode(&k); err != nil {
+				return err
+			}
+			if err := node.Content[i+1].Decode(&v); err != nil {
+				return err
+			}
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		}
+	case yaml.SequenceNode:
+		var pairs []yamlEntry[K, V]
+		if err := node.Decode(&pairs); err != nil {
+			return err
+		}
+		for _, e := range pairs {
+			entries = append(entries, Entry[K, V]{Key: e.Key, Value: e.Value})
+		}
+	case yaml.ScalarNode:
+		if node.Tag != "!!null" {
+			return fmt.Errorf("collection: cannot unmarshal YAML scalar %q into a collection", node.Value)
+		}
+	default:
+		return fmt.Errorf("collection: cannot unmarshal YAML node of kind %v into a collection", node.Kind)
+	}
+
+	c.write(func(b *writeBatch[K, V]) {
+		b.replace(entries)
+	})
+	return nil
+}
@@ -0,0 +1,82 @@
+package collection_test
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kolosys/atomic/collection"
+)
+
+// TestCollectionYAMLStringKeys tests YAML round-tripping with string keys
+func TestCollectionYAMLStringKeys(t *testing.T) {
+	c := collection.New[string, int]().Set("b", 2).Set("a", 1)
+
+	data, err := c.ToYAML()
+	if err != nil {
+		t.Fatalf("ToYAML returned error: %v", err)
+	}
+	if string(data) != "a: 1\nb: 2\n" {
+		t.Errorf("Expected a YAML mapping, got %q", data)
+	}
+
+	restored, err := collection.NewFromYAML[string, int](data)
+	if err != nil {
+		t.Fatalf("NewFromYAML returned error: %v", err)
+	}
+	if !restored.Equals(c) {
+		t.Errorf("Expected round-tripped collection to equal original, got keys %v", restored.Keys())
+	}
+}
+
+// TestCollectionYAMLOtherKeys tests YAML round-tripping with non-string keys
+func TestCollectionYAMLOtherKeys(t *testing.T) {
+	c := collection.New[int, string]().Set(2, "two").Set(1, "one")
+
+	data, err := c.ToYAML()
+	if err != nil {
+		t.Fatalf("ToYAML returned error: %v", err)
+	}
+	expected := "- key: 1\n  value: one\n- key: 2\n  value: two\n"
+	if string(data) != expected {
+		t.Errorf("Expected a YAML sequence %q, got %q", expected, data)
+	}
+
+	restored, err := collection.NewFromYAML[int, string](data)
+	if err != nil {
+		t.Fatalf("NewFromYAML returned error: %v", err)
+	}
+	if !restored.Equals(c) {
+		t.Errorf("Expected round-tripped collection to equal original, got keys %v", restored.Keys())
+	}
+
+	if _, err := collection.NewFromYAML[int, string]([]byte("just a string")); err == nil {
+		t.Error("NewFromYAML should fail for a scalar document")
+	}
+}
+
+// TestCollectionYAMLEmbedded tests Collection as a field of a YAML-serialized struct
+func TestCollectionYAMLEmbedded(t *testing.T) {
+	type Config struct {
+		Name   string                              `yaml:"name"`
+		Limits *collection.Collection[string, int] `yaml:"limits"`
+	}
+	input := "name: api\nlimits:\n  read: 100\n  write: 10\n"
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(input), &cfg); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if val, _ := cfg.Limits.Get("write"); val != 10 {
+		t.Errorf("Expected write limit 10, got %d", val)
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if !strings.Contains(string(out), "read: 100") {
+		t.Errorf("Expected marshaled config to contain limits, got %q", out)
+	}
+}
@@ -1,3 +1,10 @@
-module github.com/kolosys/atomic/collection
-
-go 1.24
\ No newline at end of file
+module github.com/kolosys/atomic/collection
+
+go 1.24
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect